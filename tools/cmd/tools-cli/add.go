@@ -5,13 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
-	"github.com/Deps-Tech/deps-registry/tools/internal/filesystem"
+	"github.com/Deps-Tech/deps-registry/tools/internal/charset"
 	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
 	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
 	"github.com/Deps-Tech/deps-registry/tools/internal/registry"
+	"github.com/Deps-Tech/deps-registry/tools/internal/report"
+	"github.com/Deps-Tech/deps-registry/tools/internal/validator"
+	"github.com/Deps-Tech/deps-registry/tools/internal/versioning"
 	"github.com/spf13/cobra"
 )
 
@@ -41,9 +46,11 @@ func init() {
 	addScriptCmd.Flags().StringVar(&sourcePath, "source", "", "Path to script file or directory")
 	addScriptCmd.Flags().StringVar(&tags, "tags", "", "Comma-separated tags")
 	addScriptCmd.MarkFlagRequired("source")
+	addReportFlags(addScriptCmd)
 
 	addDepCmd.Flags().StringVar(&sourcePath, "source", "", "Path to dependency file or directory")
 	addDepCmd.MarkFlagRequired("source")
+	addReportFlags(addDepCmd)
 
 	addCmd.AddCommand(addScriptCmd, addDepCmd)
 	rootCmd.AddCommand(addCmd)
@@ -63,7 +70,9 @@ func runAddDep(cmd *cobra.Command, args []string) {
 	}
 }
 
-func addItem(itemType, source, tagList string) error {
+func addItem(itemType, source, tagList string) (err error) {
+	checkReportFlags()
+
 	client := registry.NewClient("")
 
 	metadata, err := extractMetadata(source)
@@ -75,9 +84,13 @@ func addItem(itemType, source, tagList string) error {
 	fmt.Printf("  ID: %s\n", metadata.ID)
 	fmt.Printf("  Name: %s\n", metadata.Name)
 	fmt.Printf("  Version: %s\n", metadata.Version)
-	if metadata.Author != "" {
-		fmt.Printf("  Author: %s\n", metadata.Author)
+	if len(metadata.Authors) > 0 {
+		fmt.Printf("  Authors: %s\n", strings.Join(metadata.Authors, ", "))
+	}
+	if metadata.Directives.MoonLoader > 0 {
+		fmt.Printf("  MoonLoader: >= %d\n", metadata.Directives.MoonLoader)
 	}
+	fmt.Printf("  Encoding: %s\n", metadata.Encoding)
 
 	dupInfo, err := client.CheckDuplicate(itemType, metadata.ID, metadata.Version)
 	if err != nil && client.IsAvailable() {
@@ -109,24 +122,50 @@ func addItem(itemType, source, tagList string) error {
 		}
 	}
 
-	availableDeps := getAvailableDeps()
-	analysis, err := parser.AnalyzeLua(source, metadata.ID, availableDeps)
+	reg, err := loadRegistry(registryBasePaths())
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	ctx, err := parser.NewContext(metadata.ID, source, reg)
+	if err != nil {
+		return fmt.Errorf("failed to create context: %w", err)
+	}
+	ctx.Cache = analysisCache()
+	ctx.Analyzers = selectedAnalyzers()
+	ctx.Trace = traceOptions()
+
+	analysis, err := parser.AnalyzeWithContext(ctx, source)
 	if err != nil {
 		return fmt.Errorf("failed to analyze: %w", err)
 	}
 
 	var depVersions map[string]string
+	allDeps := append(append([]string{}, analysis.Dependencies...), analysis.OptionalDependencies...)
+	if len(allDeps)+len(analysis.ScriptDependencies) > 0 && !client.IsAvailable() {
+		fmt.Printf("\n⚠️  Warning: Cannot reach registry CDN\n")
+		fmt.Printf("   Continuing with unknown dependency versions (*)\n")
+	}
+	if len(allDeps) > 0 {
+		_, depVersions = resolveDependencies(client, "deps", allDeps)
+	}
+
+	var scriptVersions map[string]string
+	if len(analysis.ScriptDependencies) > 0 {
+		_, scriptVersions = resolveDependencies(client, "scripts", analysis.ScriptDependencies)
+	}
+
 	if len(analysis.Dependencies) > 0 {
 		fmt.Printf("\nFound dependencies:\n")
-		_, depVersions = resolveDependencies(client, analysis.Dependencies)
-		for _, dep := range analysis.Dependencies {
-			version := depVersions[dep]
-			if version == "*" {
-				fmt.Printf("  - %s (*) ⚠️  not in registry\n", dep)
-			} else {
-				fmt.Printf("  - %s (%s) ✓\n", dep, version)
-			}
-		}
+		printDependencies(analysis.Dependencies, depVersions)
+	}
+	if len(analysis.OptionalDependencies) > 0 {
+		fmt.Printf("\nFound optional dependencies (guarded by pcall):\n")
+		printDependencies(analysis.OptionalDependencies, depVersions)
+	}
+	if len(analysis.ScriptDependencies) > 0 {
+		fmt.Printf("\nFound script dependencies (import):\n")
+		printDependencies(analysis.ScriptDependencies, scriptVersions)
 	}
 
 	if analysis.UsesNetwork {
@@ -135,14 +174,74 @@ func addItem(itemType, source, tagList string) error {
 	if analysis.UsesFFI {
 		fmt.Println("\n⚠️  Uses FFI")
 	}
-	if len(analysis.Warnings) > 0 {
-		fmt.Printf("\n⚠️  %d warnings (dynamic requires detected):\n", len(analysis.Warnings))
-		for _, w := range analysis.Warnings {
-			fmt.Printf("   Line %d: %s\n", w.Line, w.Message)
+	if analysis.LuaCompat != parser.CompatLuaJIT {
+		fmt.Printf("\n❌ Requires %s, which MoonLoader's LuaJIT cannot run:\n", analysis.LuaCompat)
+		for _, f := range analysis.Compat {
+			if !f.Guarded && f.Level != parser.CompatLuaJIT {
+				fmt.Printf("   %s:%d: %s\n", f.File, f.Line, f.Feature)
+			}
+		}
+	}
+	if analysis.Precompiled {
+		fmt.Println("\n❌ Contains precompiled bytecode, analysis results are incomplete")
+	}
+	if analysis.Obfuscated {
+		fmt.Println("\n⚠️  Source looks obfuscated, analysis results may be incomplete")
+	}
+	if capabilities := parser.SummarizeCapabilities(analysis.Capabilities); len(capabilities) > 0 {
+		fmt.Printf("\nCapabilities:\n")
+		for _, c := range capabilities {
+			if c.Dynamic {
+				fmt.Printf("  - %s: %s (%s:%d, traced)\n", c.Kind, c.Detail, c.File, c.Line)
+			} else {
+				fmt.Printf("  - %s: %s (%s:%d)\n", c.Kind, c.Detail, c.File, c.Line)
+			}
+		}
+	}
+	traced := []parser.Require{}
+	for _, req := range analysis.Requires {
+		if req.Dynamic {
+			traced = append(traced, req)
+		}
+	}
+	if len(traced) > 0 {
+		fmt.Printf("\nRequires observed only by the dynamic trace:\n")
+		for _, req := range traced {
+			fmt.Printf("   %s:%d: require %q\n", req.File, req.Line, req.Module)
+		}
+	}
+	if len(analysis.Unresolved) > 0 {
+		fmt.Printf("\n⚠️  Unresolved dependencies (recorded as *):\n")
+		for _, dep := range analysis.Unresolved {
+			fmt.Printf("   %s:%d: require %q\n", dep.File, dep.Line, dep.Module)
+			if len(dep.Suggestions) > 0 {
+				fmt.Printf("      did you mean: %s\n", strings.Join(dep.Suggestions, ", "))
+			}
+		}
+	}
+	warnings := []parser.Warning{}
+	for _, w := range analysis.Warnings {
+		if w.Type != parser.WarningUnresolvedModule {
+			warnings = append(warnings, w)
+		}
+	}
+	if len(warnings) > 0 {
+		fmt.Printf("\n⚠️  %d warnings:\n", len(warnings))
+		for _, w := range warnings {
+			fmt.Printf("   %s:%d: %s\n", w.File, w.Line, w.Message)
 		}
 	}
 
 	targetPath := filepath.Join("..", itemType, metadata.ID, metadata.Version)
+	if _, statErr := os.Stat(targetPath); os.IsNotExist(statErr) {
+		// Don't leave a half-written version behind for the next validate.
+		defer func() {
+			if err != nil {
+				os.RemoveAll(targetPath)
+				os.Remove(filepath.Dir(targetPath))
+			}
+		}()
+	}
 	if err := os.MkdirAll(targetPath, 0755); err != nil {
 		return err
 	}
@@ -154,23 +253,12 @@ func addItem(itemType, source, tagList string) error {
 
 	fileMap := make(map[string]manifest.FileInfo)
 	for _, file := range files {
-		filePath := filepath.Join(targetPath, file)
-		hash, _ := filesystem.SHA256File(filePath)
-		info, _ := os.Stat(filePath)
-		fileMap[file] = manifest.FileInfo{
-			SHA256: hash,
-			Size:   info.Size(),
-		}
+		fileMap[file] = describeFile(targetPath, file)
 	}
 
-	deps := make(map[string]string)
-	for _, dep := range analysis.Dependencies {
-		if version, ok := depVersions[dep]; ok {
-			deps[dep] = version
-		} else {
-			deps[dep] = "*"
-		}
-	}
+	deps := dependencyVersions(analysis.Dependencies, depVersions)
+	optionalDeps := dependencyVersions(analysis.OptionalDependencies, depVersions)
+	scriptDeps := dependencyVersions(analysis.ScriptDependencies, scriptVersions)
 
 	tagSlice := []string{}
 	if tagList != "" {
@@ -180,22 +268,65 @@ func addItem(itemType, source, tagList string) error {
 	}
 
 	m := &manifest.Manifest{
-		ManifestVersion: "1.0",
-		ID:              metadata.ID,
-		Name:            metadata.Name,
-		Version:         metadata.Version,
-		Files:           fileMap,
-		Dependencies:    deps,
-		Security: manifest.Security{
-			NetworkAccess: analysis.UsesNetwork,
-			FileAccess:    analysis.FilePaths,
-			UsesFFI:       analysis.UsesFFI,
-		},
+		ManifestVersion:      manifest.CurrentManifestVersion,
+		ID:                   metadata.ID,
+		Name:                 metadata.Name,
+		Version:              metadata.Version,
+		Files:                fileMap,
+		Install:              manifest.DefaultInstall(itemType, metadata.ID, files),
+		Dependencies:         deps,
+		OptionalDependencies: optionalDeps,
+		ScriptDependencies:   scriptDeps,
+		LuaCompat:            string(analysis.LuaCompat),
+		Script:               scriptSection(nil, metadata.Directives),
+		Security:             securitySection(analysis),
 		Metadata: manifest.Metadata{
 			Tags: tagSlice,
 		},
 	}
 
+	nativeSection, nativeIssues := validator.InspectNative(targetPath, m)
+	m.Native = nativeSection
+	printNativeSection(nativeSection, nativeIssues)
+
+	installIssues := validator.CheckInstall(m, itemType)
+	printInstallLayout(m.InstallLayout(itemType), installIssues)
+
+	declaredIssues := validator.CheckDeclaredDependencies(m, reg)
+	if len(declaredIssues) > 0 {
+		fmt.Printf("\n⚠️  script_dependencies mismatch:\n")
+		for _, issue := range declaredIssues {
+			fmt.Printf("   %s\n", issue.Message)
+		}
+	}
+
+	dir := reportDir(targetPath)
+	findings := []report.Finding{}
+	for _, w := range analysis.Warnings {
+		findings = append(findings, report.FromWarning(metadata.ID, dir, w))
+	}
+	issues := append(append(append(nativeIssues, declaredIssues...), installIssues...), validator.InspectCompat(analysis, m)...)
+
+	data, err := manifest.Marshal(m)
+	if err != nil {
+		return err
+	}
+	schemaIssues := validator.SchemaIssues(manifest.ValidateSchema(data))
+	issues = append(issues, schemaIssues...)
+
+	for _, issue := range issues {
+		findings = append(findings, report.FromIssue(metadata.ID, dir, issue))
+	}
+	writeReport(findings)
+
+	if len(schemaIssues) > 0 {
+		fmt.Printf("\n❌ Generated dep.json does not match the manifest schema:\n")
+		for _, issue := range schemaIssues {
+			fmt.Printf("   %s: %s\n", issue.Location(), issue.Message)
+		}
+		return fmt.Errorf("invalid manifest for %s", metadata.ID)
+	}
+
 	if err := manifest.Save(targetPath, m); err != nil {
 		return err
 	}
@@ -211,10 +342,12 @@ func addItem(itemType, source, tagList string) error {
 }
 
 type Metadata struct {
-	ID      string
-	Name    string
-	Version string
-	Author  string
+	ID         string
+	Name       string
+	Version    string
+	Authors    []string
+	Encoding   string
+	Directives *parser.Directives
 }
 
 func extractMetadata(source string) (*Metadata, error) {
@@ -227,16 +360,13 @@ func extractMetadata(source string) (*Metadata, error) {
 		return nil, fmt.Errorf("no Lua files found")
 	}
 
-	content, err := os.ReadFile(luaFiles[0])
+	directives, encoding, err := readDirectives(luaFiles)
 	if err != nil {
 		return nil, err
 	}
 
-	contentStr := string(content)
-
-	name := extractField(contentStr, `script_name\s*\(\s*["'](.+?)["']\s*\)`)
-	version := extractField(contentStr, `script_version\s*\(\s*["'](.+?)["']\s*\)`)
-	author := extractField(contentStr, `script_author\s*\(\s*["'](.+?)["']\s*\)`)
+	name := directives.Name
+	version := directives.Version
 
 	if name == "" {
 		name = filepath.Base(source)
@@ -247,18 +377,100 @@ func extractMetadata(source string) (*Metadata, error) {
 		version = "1.0.0"
 	}
 
-	id := strings.ToLower(name)
+	id := strings.ToLower(charset.Transliterate(name))
 	id = regexp.MustCompile(`[^a-z0-9-]+`).ReplaceAllString(id, "-")
 	id = strings.Trim(id, "-")
 
 	return &Metadata{
-		ID:      id,
-		Name:    name,
-		Version: version,
-		Author:  author,
+		ID:         id,
+		Name:       name,
+		Version:    version,
+		Authors:    directives.Authors,
+		Encoding:   encoding,
+		Directives: directives,
 	}, nil
 }
 
+func readDirectives(luaFiles []string) (*parser.Directives, string, error) {
+	var first *parser.Directives
+	firstEncoding := ""
+
+	for i, file := range luaFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, "", err
+		}
+
+		contentStr, encoding := charset.DecodeString(content)
+		directives := parseDirectives(contentStr)
+		if i == 0 {
+			first, firstEncoding = directives, encoding
+		}
+		if directives.Name != "" {
+			return directives, encoding, nil
+		}
+	}
+
+	return first, firstEncoding, nil
+}
+
+func parseDirectives(content string) *parser.Directives {
+	if directives, err := parser.ParseDirectives(content); err == nil {
+		return directives
+	}
+
+	directives := &parser.Directives{
+		Name:         extractField(content, `script_name\s*\(\s*["'](.+?)["']\s*\)`),
+		Version:      extractField(content, `script_version\s*\(\s*["'](.+?)["']\s*\)`),
+		Authors:      []string{},
+		Properties:   []string{},
+		Dependencies: []string{},
+	}
+	if author := extractField(content, `script_author\s*\(\s*["'](.+?)["']\s*\)`); author != "" {
+		directives.Authors = append(directives.Authors, author)
+	}
+
+	return directives
+}
+
+// scriptSection fills the script section from the directives, on top of an
+// existing section so that fields without a directive are kept.
+func scriptSection(base *manifest.Script, d *parser.Directives) *manifest.Script {
+	script := &manifest.Script{}
+	if base != nil {
+		*script = *base
+	}
+
+	if d != nil {
+		if d.Description != "" {
+			script.Description = d.Description
+		}
+		if d.URL != "" {
+			script.URL = d.URL
+		}
+		if d.VersionNumber != 0 {
+			script.VersionNumber = d.VersionNumber
+		}
+		if d.MoonLoader != 0 {
+			script.MoonLoader = d.MoonLoader
+		}
+		if len(d.Authors) > 0 {
+			script.Authors = d.Authors
+		}
+		if len(d.Properties) > 0 {
+			script.Properties = d.Properties
+		}
+		if len(d.Dependencies) > 0 {
+			script.Dependencies = d.Dependencies
+		}
+	}
+
+	if reflect.DeepEqual(*script, manifest.Script{}) {
+		return nil
+	}
+	return script
+}
+
 func extractField(content, pattern string) string {
 	re := regexp.MustCompile(pattern)
 	matches := re.FindStringSubmatch(content)
@@ -352,30 +564,80 @@ func copySourceFiles(source, target string) ([]string, error) {
 	return files, err
 }
 
-func getAvailableDeps() map[string]bool {
-	deps := make(map[string]bool)
-	depsPath := filepath.Join("..", "deps")
+func printDependencies(deps []string, versions map[string]string) {
+	for _, dep := range deps {
+		version := versions[dep]
+		if version == "*" {
+			fmt.Printf("  - %s (*) ⚠️  not in registry\n", dep)
+		} else {
+			fmt.Printf("  - %s (%s) ✓\n", dep, version)
+		}
+	}
+}
 
-	items, err := os.ReadDir(depsPath)
-	if err != nil {
-		return deps
+func printNativeSection(section map[string]manifest.NativeInfo, issues []*validator.Issue) {
+	if len(section) == 0 && len(issues) == 0 {
+		return
+	}
+
+	files := make([]string, 0, len(section))
+	for file := range section {
+		files = append(files, file)
 	}
+	sort.Strings(files)
 
-	for _, item := range items {
-		if item.IsDir() {
-			deps[strings.ToLower(item.Name())] = true
+	fmt.Printf("\nNative libraries:\n")
+	for _, file := range files {
+		info := section[file]
+		fmt.Printf("  - %s (%s)\n", file, info.Machine)
+		if len(info.LuaOpen) > 0 {
+			fmt.Printf("      exports: %s\n", strings.Join(info.LuaOpen, ", "))
+		}
+		if len(info.Imports) > 0 {
+			fmt.Printf("      imports: %s\n", strings.Join(info.Imports, ", "))
 		}
 	}
 
-	return deps
+	for _, issue := range issues {
+		if issue.Fatal {
+			fmt.Printf("\n❌ %s: %s\n", issue.Location(), issue.Message)
+		} else {
+			fmt.Printf("\n⚠️  %s: %s\n", issue.Location(), issue.Message)
+		}
+	}
 }
 
-func resolveDependencies(client *registry.Client, deps []string) ([]string, map[string]string) {
-	cdnAvailable := client.IsAvailable()
-	if !cdnAvailable {
-		fmt.Printf("\n⚠️  Warning: Cannot reach registry CDN\n")
-		fmt.Printf("   Continuing with unknown dependency versions (*)\n")
+func printInstallLayout(layout map[string]string, issues []*validator.Issue) {
+	files := make([]string, 0, len(layout))
+	for file := range layout {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	fmt.Printf("\nInstall layout:\n")
+	for _, file := range files {
+		fmt.Printf("  - %s -> %s\n", filepath.ToSlash(file), layout[file])
+	}
+
+	for _, issue := range issues {
+		if issue.Fatal {
+			fmt.Printf("\n❌ %s: %s\n", issue.Location(), issue.Message)
+		} else {
+			fmt.Printf("\n⚠️  %s: %s\n", issue.Location(), issue.Message)
+		}
 	}
+}
+
+func dependencyVersions(deps []string, versions map[string]string) map[string]string {
+	result := make(map[string]string)
+	for _, dep := range deps {
+		result[dep] = versioning.CaretRange(versions[dep])
+	}
+	return result
+}
+
+func resolveDependencies(client *registry.Client, itemType string, deps []string) ([]string, map[string]string) {
+	cdnAvailable := client.IsAvailable()
 
 	versions := make(map[string]string)
 	uniqueDeps := make(map[string]bool)
@@ -389,7 +651,7 @@ func resolveDependencies(client *registry.Client, deps []string) ([]string, map[
 		result = append(result, dep)
 
 		if cdnAvailable {
-			version, err := client.GetLatestVersion("deps", dep)
+			version, err := client.GetLatestVersion(itemType, dep)
 			if err == nil {
 				versions[dep] = version
 			} else {
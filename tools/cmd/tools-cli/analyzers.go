@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	enabledAnalyzers  []string
+	disabledAnalyzers []string
+)
+
+var analyzersCmd = &cobra.Command{
+	Use:   "analyzers",
+	Short: "List the registered source analyzers",
+	Run:   runAnalyzers,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringSliceVar(&enabledAnalyzers, "analyzers", nil, "Run only these analyzers (comma-separated)")
+	rootCmd.PersistentFlags().StringSliceVar(&disabledAnalyzers, "disable-analyzers", nil, "Skip these analyzers (comma-separated)")
+	rootCmd.AddCommand(analyzersCmd)
+}
+
+func runAnalyzers(cmd *cobra.Command, args []string) {
+	selected := make(map[string]bool)
+	for _, a := range selectedAnalyzers() {
+		selected[a.Name()] = true
+	}
+
+	for _, name := range parser.AnalyzerNames() {
+		if selected[name] {
+			fmt.Printf("✓ %s\n", name)
+		} else {
+			fmt.Printf("  %s (disabled)\n", name)
+		}
+	}
+}
+
+func selectedAnalyzers() []parser.Analyzer {
+	analyzers, err := parser.SelectAnalyzers(enabledAnalyzers, disabledAnalyzers)
+	if err != nil {
+		fmt.Printf("Error selecting analyzers: %v\n", err)
+		os.Exit(1)
+	}
+	return analyzers
+}
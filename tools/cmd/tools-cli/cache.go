@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var noCache bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk analysis cache",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached analysis results",
+	Run:   runCacheClear,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Analyze every file without reading or writing the analysis cache")
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) {
+	if err := parser.NewCache(parser.DefaultCacheDir).Clear(); err != nil {
+		fmt.Printf("Failed to clear cache: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Cleared analysis cache at %s\n", parser.DefaultCacheDir)
+}
+
+func analysisCache() *parser.Cache {
+	if noCache {
+		return nil
+	}
+	return parser.NewCache(parser.DefaultCacheDir)
+}
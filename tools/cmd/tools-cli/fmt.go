@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/diff"
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var fmtCheck bool
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt",
+	Short: "Rewrite all dep.json files in canonical form",
+	Run:   runFmt,
+}
+
+func init() {
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "Print a diff for every dep.json that is not in canonical form and exit 1 without writing")
+	rootCmd.AddCommand(fmtCmd)
+}
+
+func runFmt(cmd *cobra.Command, args []string) {
+	formatted, failed := 0, 0
+
+	for _, target := range listManifests() {
+		depPath := filepath.Join(target.path, "dep.json")
+		original, err := os.ReadFile(depPath)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", target.name, err)
+			failed++
+			continue
+		}
+
+		canonical, err := formatManifest(original)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", target.name, err)
+			failed++
+			continue
+		}
+		if bytes.Equal(original, canonical) {
+			continue
+		}
+
+		formatted++
+		if fmtCheck {
+			name := filepath.ToSlash(filepath.Join(target.name, "dep.json"))
+			fmt.Printf("⚠️  %s is not in canonical form\n", target.name)
+			fmt.Print(diff.Unified("a/"+name, "b/"+name, string(original), string(canonical)))
+			continue
+		}
+
+		if err := os.WriteFile(depPath, canonical, 0644); err != nil {
+			fmt.Printf("❌ %s: %v\n", target.name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✓ Formatted %s\n", target.name)
+	}
+
+	if fmtCheck {
+		fmt.Printf("\n%d manifests not in canonical form", formatted)
+	} else {
+		fmt.Printf("\nFormatted %d manifests", formatted)
+	}
+	if failed > 0 {
+		fmt.Printf(", %d could not be formatted", failed)
+	}
+	fmt.Println()
+
+	if failed > 0 || (fmtCheck && formatted > 0) {
+		if fmtCheck && formatted > 0 {
+			fmt.Println("Run 'tools-cli fmt' to fix formatting")
+		}
+		os.Exit(1)
+	}
+}
+
+func formatManifest(data []byte) ([]byte, error) {
+	if errs := manifest.ValidateSchema(data); len(errs) > 0 {
+		return nil, fmt.Errorf("cannot format a manifest that does not match its schema: %v", errs[0])
+	}
+
+	m, err := manifest.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Marshal(m)
+}
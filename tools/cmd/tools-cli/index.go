@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/Deps-Tech/deps-registry/tools/internal/indexer"
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
 	"github.com/spf13/cobra"
 )
 
@@ -47,6 +49,19 @@ func runIndex(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	for _, version := range manifest.SchemaVersions() {
+		schema, err := manifest.Schema(version)
+		if err != nil {
+			fmt.Printf("Failed to read manifest schema: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(filepath.Join(distPath, manifest.SchemaFileName(version)), schema, 0644); err != nil {
+			fmt.Printf("Failed to write manifest schema: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	fmt.Printf("Generated index.json with %d dependencies and %d scripts\n",
 		len(idx.Dependencies), len(idx.Scripts))
+	fmt.Printf("Published manifest schemas: %s\n", strings.Join(manifest.SchemaVersions(), ", "))
 }
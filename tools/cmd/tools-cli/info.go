@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info <deps|scripts> <id> [version]",
+	Short: "Show the dependencies of a published package",
+	Args:  cobra.RangeArgs(2, 3),
+	Run:   runInfo,
+}
+
+func init() {
+	infoCmd.Flags().StringVar(&cdnURL, "cdn-url", "", "Base URL of the CDN (defaults to CDN_URL or the public registry)")
+	rootCmd.AddCommand(infoCmd)
+}
+
+func runInfo(cmd *cobra.Command, args []string) {
+	itemType, id := args[0], args[1]
+	version := ""
+	if len(args) == 3 {
+		version = args[2]
+	}
+
+	client := registry.NewClient(cdnURL)
+
+	m, err := client.GetManifest(itemType, id, version)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	optional, err := client.GetOptionalDependencies(itemType, id, m.Version)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s@%s\n", m.ID, m.Version)
+	printDependencySection("Dependencies", m.Dependencies)
+	printDependencySection("Optional dependencies", optional)
+	printDependencySection("Script dependencies", m.ScriptDependencies)
+}
+
+func printDependencySection(title string, deps map[string]string) {
+	if len(deps) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("\n%s:\n", title)
+	for _, name := range names {
+		fmt.Printf("  %s %s\n", name, deps[name])
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var listMoonLoader int
+
+var listCmd = &cobra.Command{
+	Use:   "list <deps|scripts>",
+	Short: "List published packages",
+	Args:  cobra.ExactArgs(1),
+	Run:   runList,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&cdnURL, "cdn-url", "", "Base URL of the CDN (defaults to CDN_URL or the public registry)")
+	listCmd.Flags().IntVar(&listMoonLoader, "moonloader", 0, "Only list scripts whose latest version runs on this MoonLoader version")
+	rootCmd.AddCommand(listCmd)
+}
+
+func runList(cmd *cobra.Command, args []string) {
+	client := registry.NewClient(cdnURL)
+
+	var ids []string
+	var err error
+	switch args[0] {
+	case "deps", "dependencies":
+		if listMoonLoader > 0 {
+			fmt.Println("❌ --moonloader only applies to scripts")
+			os.Exit(1)
+		}
+		ids, err = client.GetAllDependencies()
+	case "scripts":
+		if listMoonLoader > 0 {
+			ids, err = client.GetScriptsForMoonLoader(listMoonLoader)
+		} else {
+			ids, err = client.GetAllScripts()
+		}
+	default:
+		err = fmt.Errorf("unknown item type: %s", args[0])
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, id := range ids {
+		fmt.Println(id)
+	}
+}
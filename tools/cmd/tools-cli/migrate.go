@@ -1,27 +1,83 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 
+	"github.com/Deps-Tech/deps-registry/tools/internal/diff"
 	"github.com/Deps-Tech/deps-registry/tools/internal/filesystem"
 	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
 	"github.com/spf13/cobra"
 )
 
+var (
+	migrateTo     string
+	migrateVerify bool
+)
+
 var migrateCmd = &cobra.Command{
 	Use:   "migrate",
-	Short: "Migrate all dep.json to new format with SHA256 hashes",
+	Short: "Migrate all dep.json files to a newer manifest version",
 	Run:   runMigrate,
 }
 
 func init() {
+	migrateCmd.Flags().StringVar(&migrateTo, "to", manifest.CurrentManifestVersion, "Target manifest version")
+	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a diff of each migrated dep.json without writing")
+	migrateCmd.Flags().BoolVar(&migrateVerify, "verify", false, "Round-trip every registered migration against every manifest without writing")
 	rootCmd.AddCommand(migrateCmd)
 }
 
+type manifestTarget struct {
+	name string
+	path string
+}
+
 func runMigrate(cmd *cobra.Command, args []string) {
+	if _, err := manifest.Schema(migrateTo); err != nil {
+		fmt.Printf("Unknown manifest version %s (known: %s)\n", migrateTo, strings.Join(manifest.SchemaVersions(), ", "))
+		os.Exit(1)
+	}
+
+	targets := listManifests()
+
+	if migrateVerify {
+		runMigrateVerify(targets)
+		return
+	}
+
+	migrated, failed := 0, 0
+	for _, target := range targets {
+		changed, err := migrateManifest(target)
+		switch {
+		case err != nil:
+			fmt.Printf("Error migrating %s: %v\n", target.name, err)
+			failed++
+		case changed:
+			migrated++
+		}
+	}
+
+	fmt.Printf("\nMigrated %d manifests to %s", migrated, migrateTo)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	if dryRun {
+		fmt.Println("(Dry run - no files were modified)")
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func listManifests() []manifestTarget {
+	targets := []manifestTarget{}
 	for _, itemType := range []string{"deps", "scripts"} {
 		basePath := filepath.Join("..", itemType)
 		items, err := os.ReadDir(basePath)
@@ -46,36 +102,117 @@ func runMigrate(cmd *cobra.Command, args []string) {
 					continue
 				}
 
-				versionPath := filepath.Join(itemPath, version.Name())
-				if err := migrateManifest(versionPath); err != nil {
-					fmt.Printf("Error migrating %s/%s: %v\n", item.Name(), version.Name(), err)
-				} else {
-					fmt.Printf("Migrated %s/%s\n", item.Name(), version.Name())
-				}
+				targets = append(targets, manifestTarget{
+					name: item.Name() + "/" + version.Name(),
+					path: filepath.Join(itemPath, version.Name()),
+				})
 			}
 		}
 	}
+
+	return targets
+}
+
+func migrateManifest(target manifestTarget) (bool, error) {
+	depPath := filepath.Join(target.path, "dep.json")
+	original, err := os.ReadFile(depPath)
+	if err != nil {
+		return false, err
+	}
+
+	migrated, steps, err := migrateData(target.path, original, migrateTo)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(original, migrated) {
+		return false, nil
+	}
+
+	if dryRun {
+		name := filepath.ToSlash(filepath.Join(target.name, "dep.json"))
+		fmt.Printf("%s (%s)\n", target.name, strings.Join(steps, " -> "))
+		fmt.Print(diff.Unified("a/"+name, "b/"+name, string(original), string(migrated)))
+		return true, nil
+	}
+
+	if err := os.WriteFile(depPath, migrated, 0644); err != nil {
+		return false, err
+	}
+	fmt.Printf("Migrated %s (%s)\n", target.name, strings.Join(steps, " -> "))
+	return true, nil
+}
+
+func migrateData(path string, data []byte, to string) ([]byte, []string, error) {
+	var probe map[string]any
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, nil, err
+	}
+
+	steps := []string{}
+	if _, ok := probe["manifestVersion"]; !ok {
+		legacy, err := migrateLegacy(path, probe)
+		if err != nil {
+			return nil, nil, err
+		}
+		if data, err = manifest.Marshal(legacy); err != nil {
+			return nil, nil, err
+		}
+		steps = append(steps, "legacy", manifest.Version1)
+	}
+
+	raw, applied, err := manifest.Migrate(data, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, m := range applied {
+		if i == 0 && len(steps) == 0 {
+			steps = append(steps, m.From)
+		}
+		steps = append(steps, m.To)
+	}
+	if len(steps) == 0 {
+		return data, steps, nil
+	}
+
+	migrated, err := renderMigrated(data, raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrated, steps, nil
 }
 
-func migrateManifest(path string) error {
-	depPath := filepath.Join(path, "dep.json")
-	data, err := os.ReadFile(depPath)
+func renderMigrated(original []byte, raw map[string]any) ([]byte, error) {
+	data, err := json.Marshal(raw)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var old map[string]interface{}
-	if err := json.Unmarshal(data, &old); err != nil {
-		return err
+	existing := map[string]bool{}
+	for _, e := range manifest.ValidateSchema(original) {
+		existing[e.Pointer+": "+e.Message] = true
 	}
 
-	if _, ok := old["manifestVersion"]; ok {
-		return nil
+	introduced := []string{}
+	for _, e := range manifest.ValidateSchema(data) {
+		if message := e.Pointer + ": " + e.Message; !existing[message] {
+			introduced = append(introduced, message)
+		}
+	}
+	if len(introduced) > 0 {
+		return nil, fmt.Errorf("migrated manifest does not match its schema: %s", strings.Join(introduced, "; "))
+	}
+
+	m, err := manifest.Parse(data)
+	if err != nil {
+		return nil, err
 	}
+	return manifest.Marshal(m)
+}
 
+func migrateLegacy(path string, old map[string]interface{}) (*manifest.Manifest, error) {
 	files, err := filesystem.ListFiles(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	fileMap := make(map[string]manifest.FileInfo)
@@ -97,8 +234,8 @@ func migrateManifest(path string) error {
 		}
 	}
 
-	m := &manifest.Manifest{
-		ManifestVersion: "1.0",
+	return &manifest.Manifest{
+		ManifestVersion: manifest.Version1,
 		ID:              getString(old, "id"),
 		Name:            getString(old, "name"),
 		Version:         getString(old, "version"),
@@ -112,9 +249,140 @@ func migrateManifest(path string) error {
 			SourceURL: getString(old, "sourceUrl"),
 			Tags:      getStringSlice(old, "tags"),
 		},
+	}, nil
+}
+
+func runMigrateVerify(targets []manifestTarget) {
+	versions := manifest.SchemaVersions()
+	checked, failed := 0, 0
+
+	for _, target := range targets {
+		data, err := os.ReadFile(filepath.Join(target.path, "dep.json"))
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", target.name, err)
+			failed++
+			continue
+		}
+
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			fmt.Printf("❌ %s: %v\n", target.name, err)
+			failed++
+			continue
+		}
+		from, _ := raw["manifestVersion"].(string)
+		if from == "" {
+			fmt.Printf("⚠️  %s: legacy manifest, run migrate first\n", target.name)
+			continue
+		}
+
+		for _, to := range versions {
+			if path, err := manifest.MigrationPath(from, to); err != nil || len(path) == 0 {
+				continue
+			}
+
+			checked++
+			if problems := verifyMigration(data, to); len(problems) > 0 {
+				failed++
+				for _, problem := range problems {
+					fmt.Printf("❌ %s (%s -> %s): %s\n", target.name, from, to, problem)
+				}
+				continue
+			}
+			fmt.Printf("✓ %s (%s -> %s)\n", target.name, from, to)
+		}
 	}
 
-	return manifest.Save(path, m)
+	fmt.Printf("\nVerified %d migrations, %d failed\n", checked, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func verifyMigration(data []byte, to string) []string {
+	raw, _, err := manifest.Migrate(data, to)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	migrated, err := renderMigrated(data, raw)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	problems := []string{}
+
+	rawData, _ := json.Marshal(raw)
+	var m manifest.Manifest
+	json.Unmarshal(rawData, &m)
+	if roundTrip, _ := json.Marshal(&m); !sameJSON(rawData, roundTrip) {
+		problems = append(problems, "fields were lost converting the migrated JSON to a manifest")
+	}
+
+	again, applied, err := manifest.Migrate(migrated, to)
+	if err != nil {
+		problems = append(problems, "re-migrating: "+err.Error())
+	} else if len(applied) > 0 {
+		problems = append(problems, "migrating the result again is not a no-op")
+	} else if againData, _ := json.Marshal(again); !sameJSON(againData, migrated) {
+		problems = append(problems, "migrating the result again changed it")
+	}
+
+	var before, after map[string]any
+	json.Unmarshal(data, &before)
+	json.Unmarshal(migrated, &after)
+	for _, key := range []string{"id", "version", "files"} {
+		if !reflect.DeepEqual(before[key], after[key]) {
+			problems = append(problems, key+" changed")
+		}
+	}
+
+	return problems
+}
+
+func sameJSON(a, b []byte) bool {
+	var left, right any
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(pruneEmpty(left), pruneEmpty(right))
+}
+
+func pruneEmpty(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			item = pruneEmpty(item)
+			if isEmptyJSON(item) {
+				delete(v, key)
+				continue
+			}
+			v[key] = item
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = pruneEmpty(item)
+		}
+		return v
+	}
+	return value
+}
+
+func isEmptyJSON(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+	return false
 }
 
 func getString(m map[string]interface{}, key string) string {
@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 
+	"github.com/Deps-Tech/deps-registry/tools/internal/charset"
 	"github.com/Deps-Tech/deps-registry/tools/internal/filesystem"
 	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
 	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+	"github.com/Deps-Tech/deps-registry/tools/internal/pool"
 	"github.com/Deps-Tech/deps-registry/tools/internal/registry"
+	"github.com/Deps-Tech/deps-registry/tools/internal/report"
 	"github.com/Deps-Tech/deps-registry/tools/internal/validator"
 	"github.com/Deps-Tech/deps-registry/tools/internal/versioning"
 	"github.com/spf13/cobra"
@@ -17,6 +23,7 @@ import (
 var (
 	dryRun      bool
 	skipValidation bool
+	jobs        int
 )
 
 var regenerateCmd = &cobra.Command{
@@ -29,16 +36,17 @@ var regenerateCmd = &cobra.Command{
 func init() {
 	regenerateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be changed without writing")
 	regenerateCmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "Skip validation checks")
+	regenerateCmd.Flags().IntVar(&jobs, "jobs", pool.DefaultJobs(), "Number of packages to analyze in parallel")
+	addReportFlags(regenerateCmd)
 	rootCmd.AddCommand(regenerateCmd)
 }
 
 func runRegenerate(cmd *cobra.Command, args []string) {
+	checkReportFlags()
+
 	fmt.Println("Starting manifest regeneration...")
 
-	basePaths := []string{
-		filepath.Join("..", "deps"),
-		filepath.Join("..", "scripts"),
-	}
+	basePaths := registryBasePaths()
 
 	allManifests := make(map[string]*manifest.Manifest)
 	itemPaths := make(map[string]string)
@@ -101,100 +109,306 @@ func runRegenerate(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	reg, err := parser.LoadRegistryFromManifests(basePaths)
+	reg, err := loadRegistry(basePaths)
 	if err != nil {
 		fmt.Printf("Error loading registry: %v\n", err)
 		os.Exit(1)
 	}
 
-	for pkgID, aliases := range registry.WellKnownAliases {
-		if pkg := reg.GetPackage(pkgID); pkg != nil {
-			pkg.Provides = aliases
-		}
+	ids := make([]string, 0, len(allManifests))
+	for id := range allManifests {
+		ids = append(ids, id)
 	}
+	sort.Strings(ids)
+
+	cache := analysisCache()
+	analyzers := selectedAnalyzers()
+	results := pool.Map(jobs, ids, func(id string) *regenerateResult {
+		return regeneratePackage(id, allManifests[id], itemPaths[id], reg, cache, analyzers, basePaths)
+	})
 
 	updated := 0
 	skipped := 0
-	errors := 0
+	failed := []error{}
+	findings := []report.Finding{}
+
+	for _, result := range results {
+		fmt.Print(result.output.String())
+		findings = append(findings, result.findings...)
+		switch {
+		case result.err != nil:
+			failed = append(failed, result.err)
+		case result.updated:
+			updated++
+		default:
+			skipped++
+		}
+	}
+
+	fmt.Printf("\n=== Summary ===\n")
+	fmt.Printf("Updated: %d\n", updated)
+	fmt.Printf("Skipped: %d\n", skipped)
+	if len(failed) > 0 {
+		fmt.Printf("Errors:  %d\n", len(failed))
+		for _, err := range failed {
+			fmt.Printf("  - %v\n", err)
+		}
+	}
+	if dryRun {
+		fmt.Println("\n(Dry run - no files were modified)")
+	}
 
-	for id, m := range allManifests {
-		versionPath := itemPaths[id]
+	writeReport(findings)
+}
 
-		ctx, err := parser.NewContext(id, versionPath, reg)
-		if err != nil {
-			fmt.Printf("❌ %s: failed to create context: %v\n", id, err)
-			errors++
-			continue
+type regenerateResult struct {
+	output   strings.Builder
+	findings []report.Finding
+	updated  bool
+	err      error
+}
+
+func regeneratePackage(id string, m *manifest.Manifest, versionPath string, reg *parser.Registry, cache *parser.Cache, analyzers []parser.Analyzer, basePaths []string) *regenerateResult {
+	result := &regenerateResult{}
+
+	ctx, err := parser.NewContext(id, versionPath, reg)
+	if err != nil {
+		result.err = fmt.Errorf("%s: failed to create context: %w", id, err)
+		fmt.Fprintf(&result.output, "❌ %v\n", result.err)
+		return result
+	}
+	ctx.Cache = cache
+	ctx.Analyzers = analyzers
+	ctx.Trace = traceOptions()
+
+	analysis, err := parser.AnalyzeWithContext(ctx, versionPath)
+	if err != nil {
+		result.err = fmt.Errorf("%s: analysis failed: %w", id, err)
+		fmt.Fprintf(&result.output, "❌ %v\n", result.err)
+		return result
+	}
+
+	dir := reportDir(versionPath)
+	for _, w := range analysis.Warnings {
+		result.findings = append(result.findings, report.FromWarning(id, dir, w))
+	}
+
+	newDeps := latestVersions(analysis.Dependencies, basePaths)
+	newOptionalDeps := latestVersions(analysis.OptionalDependencies, basePaths)
+	newScriptDeps := latestVersions(analysis.ScriptDependencies, basePaths)
+	if m.IsV2() {
+		newDeps = keepRanges(m.Dependencies, newDeps)
+		newOptionalDeps = keepRanges(m.OptionalDependencies, newOptionalDeps)
+		newScriptDeps = keepRanges(m.ScriptDependencies, newScriptDeps)
+	}
+
+	providesAliases := registry.GetAliases(id)
+	nativeSection, _ := validator.InspectNative(versionPath, m)
+	security := securitySection(analysis)
+	script := m.Script
+	if luaFiles, err := findLuaFiles(versionPath); err == nil && len(luaFiles) > 0 {
+		if directives, _, err := readDirectives(luaFiles); err == nil {
+			script = scriptSection(m.Script, directives)
 		}
+	}
 
-		analysis, err := parser.AnalyzeWithContext(ctx, versionPath)
-		if err != nil {
-			fmt.Printf("❌ %s: analysis failed: %v\n", id, err)
-			errors++
-			continue
+	fileMap := make(map[string]manifest.FileInfo)
+	for fileName := range m.Files {
+		fileMap[fileName] = describeFile(versionPath, fileName)
+	}
+
+	changed := false
+	for fileName, info := range fileMap {
+		if m.Files[fileName].Encoding != info.Encoding {
+			changed = true
 		}
+	}
+	if !mapsEqual(m.Dependencies, newDeps) {
+		changed = true
+	}
+	if !mapsEqual(m.OptionalDependencies, newOptionalDeps) {
+		changed = true
+	}
+	if !mapsEqual(m.ScriptDependencies, newScriptDeps) {
+		changed = true
+	}
+	if !slicesEqual(m.Provides, providesAliases) {
+		changed = true
+	}
+	if !validator.NativeSectionsEqual(m.Native, nativeSection) {
+		changed = true
+	}
+	if !securityEqual(m.Security, security) {
+		changed = true
+	}
+	if !reflect.DeepEqual(m.Script, script) {
+		changed = true
+	}
+	if m.LuaCompat != string(analysis.LuaCompat) {
+		changed = true
+	}
 
-		newDeps := make(map[string]string)
-		for _, dep := range analysis.Dependencies {
-			version := getLatestVersionForDep(dep, basePaths)
-			if version != "" {
-				newDeps[dep] = version
+	if changed {
+		m.Dependencies = newDeps
+		m.OptionalDependencies = newOptionalDeps
+		m.ScriptDependencies = newScriptDeps
+		m.Provides = providesAliases
+		m.Native = nativeSection
+		m.Security = security
+		m.Script = script
+		m.LuaCompat = string(analysis.LuaCompat)
+
+		m.Files = fileMap
+
+		if !dryRun {
+			if err := manifest.Save(versionPath, m); err != nil {
+				result.err = fmt.Errorf("%s: failed to save: %w", id, err)
+				fmt.Fprintf(&result.output, "❌ %v\n", result.err)
+				return result
 			}
 		}
 
-		providesAliases := registry.GetAliases(id)
+		fmt.Fprintf(&result.output, "✓ %s\n", id)
+		if len(analysis.Warnings) > 0 {
+			fmt.Fprintf(&result.output, "  ⚠️  %d warnings\n", len(analysis.Warnings))
+		}
+		result.updated = true
+	}
 
-		changed := false
-		if !mapsEqual(m.Dependencies, newDeps) {
-			changed = true
+	for _, issue := range validator.CheckDeclaredDependencies(m, reg) {
+		fmt.Fprintf(&result.output, "  ⚠️  %s: %s\n", id, issue.Message)
+		result.findings = append(result.findings, report.FromIssue(id, dir, issue))
+	}
+
+	return result
+}
+
+func registryBasePaths() []string {
+	return []string{
+		filepath.Join("..", "deps"),
+		filepath.Join("..", "scripts"),
+	}
+}
+
+func loadRegistry(basePaths []string) (*parser.Registry, error) {
+	reg, err := parser.LoadRegistryFromManifests(basePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	for pkgID, aliases := range registry.WellKnownAliases {
+		if pkg := reg.GetPackage(pkgID); pkg != nil {
+			pkg.Provides = aliases
 		}
-		if !slicesEqual(m.Provides, providesAliases) {
-			changed = true
+	}
+
+	return reg, nil
+}
+
+func describeFile(versionPath, fileName string) manifest.FileInfo {
+	filePath := filepath.Join(versionPath, fileName)
+	hash, _ := filesystem.SHA256File(filePath)
+	info, _ := os.Stat(filePath)
+
+	fileInfo := manifest.FileInfo{
+		SHA256: hash,
+		Size:   info.Size(),
+	}
+
+	switch filepath.Ext(fileName) {
+	case ".lua", ".luac":
+		if content, err := os.ReadFile(filePath); err == nil && !parser.IsBytecode(string(content)) {
+			fileInfo.Encoding = charset.Detect(content)
 		}
+	}
 
-		if changed {
-			m.Dependencies = newDeps
-			m.Provides = providesAliases
+	return fileInfo
+}
 
-			fileMap := make(map[string]manifest.FileInfo)
-			for fileName := range m.Files {
-				filePath := filepath.Join(versionPath, fileName)
-				hash, _ := filesystem.SHA256File(filePath)
-				info, _ := os.Stat(filePath)
-				fileMap[fileName] = manifest.FileInfo{
-					SHA256: hash,
-					Size:   info.Size(),
-				}
-			}
-			m.Files = fileMap
+func securityCapabilities(capabilities []parser.Capability) []manifest.Capability {
+	result := []manifest.Capability{}
+	for _, capability := range parser.SummarizeCapabilities(capabilities) {
+		result = append(result, manifest.Capability{
+			Kind:    string(capability.Kind),
+			Detail:  capability.Detail,
+			File:    capability.File,
+			Line:    capability.Line,
+			Dynamic: capability.Dynamic,
+		})
+	}
+	return result
+}
 
-			if !dryRun {
-				if err := manifest.Save(versionPath, m); err != nil {
-					fmt.Printf("❌ %s: failed to save: %v\n", id, err)
-					errors++
-					continue
-				}
-			}
+// securitySection derives the summary flags from the capability list, so that
+// usesFFI, networkAccess and fileAccess always agree with it.
+func securitySection(analysis *parser.Analysis) manifest.Security {
+	security := manifest.Security{
+		Capabilities: securityCapabilities(analysis.Capabilities),
+		Precompiled:  analysis.Precompiled,
+		Obfuscated:   analysis.Obfuscated,
+	}
 
-			fmt.Printf("✓ %s\n", id)
-			if len(analysis.Warnings) > 0 {
-				fmt.Printf("  ⚠️  %d warnings (dynamic requires detected)\n", len(analysis.Warnings))
+	files := make(map[string]bool)
+	for _, capability := range security.Capabilities {
+		switch parser.CapabilityKind(capability.Kind) {
+		case parser.CapabilityNetwork, parser.CapabilityDownload:
+			security.NetworkAccess = true
+		case parser.CapabilityFFI:
+			security.UsesFFI = true
+		case parser.CapabilityFileAccess:
+			if capability.Detail != "" && !files[capability.Detail] {
+				files[capability.Detail] = true
+				security.FileAccess = append(security.FileAccess, capability.Detail)
 			}
-			updated++
-		} else {
-			skipped++
 		}
 	}
+	sort.Strings(security.FileAccess)
 
-	fmt.Printf("\n=== Summary ===\n")
-	fmt.Printf("Updated: %d\n", updated)
-	fmt.Printf("Skipped: %d\n", skipped)
-	if errors > 0 {
-		fmt.Printf("Errors:  %d\n", errors)
+	return security
+}
+
+func securityEqual(a, b manifest.Security) bool {
+	return a.NetworkAccess == b.NetworkAccess &&
+		a.UsesFFI == b.UsesFFI &&
+		slicesEqual(a.FileAccess, b.FileAccess) &&
+		capabilitiesEqual(a.Capabilities, b.Capabilities) &&
+		a.Precompiled == b.Precompiled &&
+		a.Obfuscated == b.Obfuscated
+}
+
+func capabilitiesEqual(a, b []manifest.Capability) bool {
+	if len(a) != len(b) {
+		return false
 	}
-	if dryRun {
-		fmt.Println("\n(Dry run - no files were modified)")
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func latestVersions(deps []string, basePaths []string) map[string]string {
+	versions := make(map[string]string)
+	for _, dep := range deps {
+		version := getLatestVersionForDep(dep, basePaths)
+		if version != "" {
+			versions[dep] = version
+		}
+	}
+	return versions
+}
+
+func keepRanges(declared, latest map[string]string) map[string]string {
+	ranges := make(map[string]string)
+	for dep, version := range latest {
+		if constraint, ok := declared[dep]; ok && versioning.Satisfies(version, constraint) {
+			ranges[dep] = constraint
+		} else {
+			ranges[dep] = versioning.CaretRange(version)
+		}
 	}
+	return ranges
 }
 
 func getLatestVersionForDep(depID string, basePaths []string) string {
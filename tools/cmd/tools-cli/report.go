@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFile   string
+	reportFormat string
+)
+
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&reportFile, "report", "", "Write findings to this file")
+	cmd.Flags().StringVar(&reportFormat, "report-format", report.FormatSARIF, "Report format: sarif or json")
+}
+
+func checkReportFlags() {
+	if reportFile == "" {
+		return
+	}
+	switch reportFormat {
+	case report.FormatSARIF, report.FormatJSON:
+	default:
+		fmt.Printf("Unknown report format %q, expected sarif or json\n", reportFormat)
+		os.Exit(1)
+	}
+}
+
+func writeReport(findings []report.Finding) {
+	if reportFile == "" {
+		return
+	}
+
+	report.Sort(findings)
+	if err := report.WriteFile(reportFile, reportFormat, Version, findings); err != nil {
+		fmt.Printf("Failed to write report: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nWrote %d findings to %s\n", len(findings), reportFile)
+}
+
+func reportDir(path string) string {
+	rel, err := filepath.Rel("..", path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
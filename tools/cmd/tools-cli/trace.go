@@ -0,0 +1,32 @@
+package main
+
+import (
+	"time"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+)
+
+var (
+	traceEnabled      bool
+	traceTimeout      time.Duration
+	traceInstructions int64
+	traceMemory       int64
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&traceEnabled, "trace", false, "Run entry files in a sandboxed Lua VM to trace requires computed at runtime")
+	rootCmd.PersistentFlags().DurationVar(&traceTimeout, "trace-timeout", parser.DefaultTraceTimeout, "Wall-clock budget for tracing each entry file")
+	rootCmd.PersistentFlags().Int64Var(&traceInstructions, "trace-instructions", parser.DefaultTraceInstructions, "Instruction budget for tracing each entry file")
+	rootCmd.PersistentFlags().Int64Var(&traceMemory, "trace-memory", parser.DefaultTraceMemory, "Budget in bytes for strings built by string.rep and table.concat while tracing each entry file")
+}
+
+func traceOptions() *parser.TraceOptions {
+	if !traceEnabled {
+		return nil
+	}
+	return &parser.TraceOptions{
+		Timeout:      traceTimeout,
+		Instructions: traceInstructions,
+		Memory:       traceMemory,
+	}
+}
@@ -2,14 +2,21 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+	"github.com/Deps-Tech/deps-registry/tools/internal/pool"
+	"github.com/Deps-Tech/deps-registry/tools/internal/report"
 	"github.com/Deps-Tech/deps-registry/tools/internal/validator"
 	"github.com/spf13/cobra"
 )
 
+var strict bool
+
 var validateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate all manifests in the registry",
@@ -17,13 +24,28 @@ var validateCmd = &cobra.Command{
 }
 
 func init() {
+	validateCmd.Flags().BoolVar(&strict, "strict", false, "Fail on dependencies that do not resolve to a registry package")
+	validateCmd.Flags().IntVar(&jobs, "jobs", pool.DefaultJobs(), "Number of packages to validate in parallel")
+	addReportFlags(validateCmd)
 	rootCmd.AddCommand(validateCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) {
+	checkReportFlags()
+
 	hasErrors := false
+	findings := []report.Finding{}
 	allManifests := make(map[string]*manifest.Manifest)
 
+	reg, err := loadRegistry(registryBasePaths())
+	if err != nil {
+		fmt.Printf("Error loading registry: %v\n", err)
+		os.Exit(1)
+	}
+
+	analyzers := selectedAnalyzers()
+	targets := []validateTarget{}
+
 	for _, itemType := range []string{"deps", "scripts"} {
 		basePath := filepath.Join("..", itemType)
 		items, err := os.ReadDir(basePath)
@@ -49,21 +71,37 @@ func runValidate(cmd *cobra.Command, args []string) {
 					continue
 				}
 
-				versionPath := filepath.Join(itemPath, version.Name())
-				m, err := validateManifest(versionPath)
-				if err != nil {
-					fmt.Printf("❌ %s/%s: %v\n", item.Name(), version.Name(), err)
-					hasErrors = true
-				} else {
-					fmt.Printf("✓ %s/%s\n", item.Name(), version.Name())
-					if m != nil {
-						allManifests[m.ID] = m
-					}
-				}
+				targets = append(targets, validateTarget{
+					name:     item.Name() + "/" + version.Name(),
+					path:     filepath.Join(itemPath, version.Name()),
+					itemType: itemType,
+					id:       item.Name(),
+					version:  version.Name(),
+				})
 			}
 		}
 	}
 
+	available := make(map[string][]string)
+	for _, target := range targets {
+		available[target.id] = append(available[target.id], target.version)
+	}
+
+	results := pool.Map(jobs, targets, func(target validateTarget) *validateResult {
+		return validateVersion(target, reg, analyzers, available)
+	})
+
+	for _, result := range results {
+		fmt.Print(result.output.String())
+		findings = append(findings, result.findings...)
+		if result.failed {
+			hasErrors = true
+		}
+		if result.manifest != nil {
+			allManifests[result.manifest.ID] = result.manifest
+		}
+	}
+
 	if !hasErrors {
 		fmt.Println("\nRunning dependency graph validation...")
 
@@ -85,6 +123,8 @@ func runValidate(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	writeReport(findings)
+
 	if hasErrors {
 		os.Exit(1)
 	}
@@ -92,6 +132,103 @@ func runValidate(cmd *cobra.Command, args []string) {
 	fmt.Println("\nAll manifests are valid")
 }
 
+type validateTarget struct {
+	name     string
+	path     string
+	itemType string
+	id       string
+	version  string
+}
+
+type validateResult struct {
+	output   strings.Builder
+	manifest *manifest.Manifest
+	findings []report.Finding
+	failed   bool
+}
+
+func validateVersion(target validateTarget, reg *parser.Registry, analyzers []parser.Analyzer, available map[string][]string) *validateResult {
+	result := &validateResult{}
+
+	dir := reportDir(target.path)
+
+	issues := validator.InspectSchema(target.path)
+
+	m, err := validateManifest(target.path)
+	if err != nil {
+		issues = append(issues, &validator.Issue{
+			File:    "dep.json",
+			Rule:    validator.RuleManifest,
+			Fatal:   true,
+			Message: err.Error(),
+		})
+		result.failed = printIssues(&result.output, target.name, issues)
+		for _, issue := range issues {
+			result.findings = append(result.findings, report.FromIssue(target.name, dir, issue))
+		}
+		return result
+	}
+
+	natives, nativeIssues := validator.InspectNative(target.path, m)
+	issues = append(issues, nativeIssues...)
+	issues = append(issues, validator.CheckDeclaredDependencies(m, reg)...)
+	issues = append(issues, validator.CheckVersionRanges(m, available)...)
+	issues = append(issues, validator.CheckInstall(m, target.itemType)...)
+	issues = append(issues, inspectAnalysis(target.path, m, reg, analyzers, natives)...)
+	result.failed = printIssues(&result.output, target.name, issues)
+	for _, issue := range issues {
+		result.findings = append(result.findings, report.FromIssue(m.ID, dir, issue))
+	}
+	result.manifest = m
+
+	return result
+}
+
+func inspectAnalysis(path string, m *manifest.Manifest, reg *parser.Registry, analyzers []parser.Analyzer, natives map[string]manifest.NativeInfo) []*validator.Issue {
+	ctx, err := parser.NewContext(m.ID, path, reg)
+	if err != nil {
+		return []*validator.Issue{{File: "dep.json", Rule: validator.RuleAnalysis, Fatal: true, Message: err.Error()}}
+	}
+	ctx.Cache = analysisCache()
+	ctx.Analyzers = analyzers
+	ctx.Trace = traceOptions()
+
+	analysis, err := parser.AnalyzeWithContext(ctx, path)
+	if err != nil {
+		return []*validator.Issue{{File: "dep.json", Rule: validator.RuleAnalysis, Fatal: true, Message: err.Error()}}
+	}
+
+	issues := validator.InspectSources(analysis)
+	issues = append(issues, validator.InspectCompat(analysis, m)...)
+	issues = append(issues, validator.InspectModuleGraph(m, analysis, natives)...)
+	return append(issues, validator.CheckUnresolved(analysis, strict)...)
+}
+
+func printIssues(w io.Writer, name string, issues []*validator.Issue) bool {
+	fatal := false
+	for _, issue := range issues {
+		if issue.Fatal {
+			fatal = true
+		}
+	}
+
+	if fatal {
+		fmt.Fprintf(w, "❌ %s\n", name)
+	} else {
+		fmt.Fprintf(w, "✓ %s\n", name)
+	}
+
+	for _, issue := range issues {
+		if issue.Fatal {
+			fmt.Fprintf(w, "   ❌ %s: %s\n", issue.Location(), issue.Message)
+		} else {
+			fmt.Fprintf(w, "   ⚠️  %s: %s\n", issue.Location(), issue.Message)
+		}
+	}
+
+	return fatal
+}
+
 func validateManifest(path string) (*manifest.Manifest, error) {
 	m, err := manifest.Load(path)
 	if err != nil {
@@ -4,6 +4,8 @@ go 1.23
 
 require github.com/spf13/cobra v1.8.1
 
+require github.com/yuin/gopher-lua v1.1.1
+
 require (
 	github.com/Masterminds/semver/v3 v3.2.1 // direct
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
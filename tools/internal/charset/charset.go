@@ -0,0 +1,114 @@
+package charset
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	UTF8    = "utf-8"
+	UTF8BOM = "utf-8-bom"
+	CP1251  = "cp1251"
+	CP866   = "cp866"
+)
+
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+const frequentLetters = "оеаинтсрвлкмдпу"
+
+const punctuation = "\u00a0\u00ad‚„…†‡€‰‹›‘’“”•–—™№«»§°©®±¶·µ¦¤¬"
+
+func Detect(data []byte) string {
+	if bytes.HasPrefix(data, bom) {
+		return UTF8BOM
+	}
+
+	if utf8.Valid(data) {
+		return UTF8
+	}
+
+	if cyrillicScore(data, &cp866) > cyrillicScore(data, &cp1251) {
+		return CP866
+	}
+	return CP1251
+}
+
+func Decode(data []byte, encoding string) string {
+	switch encoding {
+	case UTF8BOM:
+		return string(bytes.TrimPrefix(data, bom))
+	case CP1251:
+		return decodeTable(data, &cp1251)
+	case CP866:
+		return decodeTable(data, &cp866)
+	default:
+		return string(data)
+	}
+}
+
+func DecodeString(data []byte) (string, string) {
+	encoding := Detect(data)
+	return Decode(data, encoding), encoding
+}
+
+func decodeTable(data []byte, table *[128]rune) string {
+	var sb strings.Builder
+	sb.Grow(len(data))
+
+	for _, b := range data {
+		if b < 0x80 {
+			sb.WriteByte(b)
+		} else {
+			sb.WriteRune(table[b-0x80])
+		}
+	}
+
+	return sb.String()
+}
+
+func cyrillicScore(data []byte, table *[128]rune) int {
+	score := 0
+	for _, b := range data {
+		if b < 0x80 {
+			continue
+		}
+		r := table[b-0x80]
+		switch {
+		case strings.ContainsRune(frequentLetters, r):
+			score += 3
+		case (r >= 'А' && r <= 'я') || r == 'Ё' || r == 'ё' || strings.ContainsRune(punctuation, r):
+			score++
+		}
+	}
+	return score
+}
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+func Transliterate(s string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		lower := unicode.ToLower(r)
+		latin, ok := translit[lower]
+		if !ok {
+			sb.WriteRune(r)
+			continue
+		}
+		if lower != r && latin != "" {
+			latin = strings.ToUpper(latin[:1]) + latin[1:]
+		}
+		sb.WriteString(latin)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+package charset
+
+import "testing"
+
+const russian = `script_name("Помощник")
+script_description("Проверка документов и настройка интерфейса")`
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"ascii", []byte(`script_name("hud")`), UTF8},
+		{"utf-8", []byte(russian), UTF8},
+		{"utf-8 with bom", append([]byte{0xEF, 0xBB, 0xBF}, russian...), UTF8BOM},
+		{"cp1251", encode(russian, &cp1251), CP1251},
+		{"cp866", encode(russian, &cp866), CP866},
+		{"cp1251 punctuation", encode("-- «Помощник» — версия №2", &cp1251), CP1251},
+		{"cp866 box drawing", encode("-- ╔══ Помощник ══╗", &cp866), CP866},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.data); got != tt.want {
+				t.Errorf("Detect = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		want         string
+		wantEncoding string
+	}{
+		{"utf-8", []byte(russian), russian, UTF8},
+		{"utf-8 with bom", append([]byte{0xEF, 0xBB, 0xBF}, russian...), russian, UTF8BOM},
+		{"cp1251", encode(russian, &cp1251), russian, CP1251},
+		{"cp866", encode(russian, &cp866), russian, CP866},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, encoding := DecodeString(tt.data)
+			if got != tt.want || encoding != tt.wantEncoding {
+				t.Errorf("DecodeString = %q (%s), want %q (%s)", got, encoding, tt.want, tt.wantEncoding)
+			}
+		})
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Помощник", "Pomoshchnik"},
+		{"Жёлтый чат", "Zheltyy chat"},
+		{"Объявления", "Obyavleniya"},
+		{"hud v2", "hud v2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Transliterate(tt.in); got != tt.want {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func encode(s string, table *[128]rune) []byte {
+	bytes := make(map[rune]byte, len(table))
+	for i, r := range table {
+		bytes[r] = byte(0x80 + i)
+	}
+
+	data := []byte{}
+	for _, r := range s {
+		if r < 0x80 {
+			data = append(data, byte(r))
+			continue
+		}
+		b, ok := bytes[r]
+		if !ok {
+			panic("no byte for " + string(r))
+		}
+		data = append(data, b)
+	}
+	return data
+}
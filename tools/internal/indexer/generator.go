@@ -92,12 +92,18 @@ func generateForType(itemType, distPath, cdnURL string) (map[string]PackageInfo,
 			}
 
 			url := fmt.Sprintf("%s/%s/%s", cdnURL, itemType, fileName)
-			pkgInfo.Versions[version] = VersionInfo{
+			versionInfo := VersionInfo{
 				URL:      url,
 				SHA256:   hash,
 				Size:     info.Size(),
+				Install:  m.InstallLayout(itemType),
 				Manifest: *m,
 			}
+			if m.Script != nil {
+				versionInfo.MoonLoader = m.Script.MoonLoader
+				versionInfo.Authors = m.Script.Authors
+			}
+			pkgInfo.Versions[version] = versionInfo
 		}
 
 		result[pkgName] = pkgInfo
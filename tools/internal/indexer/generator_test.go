@@ -0,0 +1,139 @@
+package indexer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+)
+
+func TestGenerateForType(t *testing.T) {
+	tests := []struct {
+		name       string
+		versions   map[string]*manifest.Script
+		latest     string
+		moonloader map[string]int
+		authors    map[string][]string
+	}{
+		{
+			name:       "library",
+			versions:   map[string]*manifest.Script{"1.0": nil},
+			latest:     "1.0",
+			moonloader: map[string]int{"1.0": 0},
+			authors:    map[string][]string{"1.0": nil},
+		},
+		{
+			name: "script directives",
+			versions: map[string]*manifest.Script{
+				"1.9":  {MoonLoader: 26, Authors: []string{"alice"}},
+				"1.10": {MoonLoader: 27, Authors: []string{"alice", "bob"}},
+			},
+			latest:     "1.10",
+			moonloader: map[string]int{"1.9": 26, "1.10": 27},
+			authors:    map[string][]string{"1.9": {"alice"}, "1.10": {"alice", "bob"}},
+		},
+		{
+			name: "two-part versions",
+			versions: map[string]*manifest.Script{
+				"1.0": {MoonLoader: 26},
+				"2.0": {MoonLoader: 26},
+			},
+			latest:     "2.0",
+			moonloader: map[string]int{"1.0": 26, "2.0": 26},
+			authors:    map[string][]string{"1.0": nil, "2.0": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dist := t.TempDir()
+			for version, script := range tt.versions {
+				writePackage(t, dist, "hud", version, script)
+			}
+
+			index, err := generateForType("scripts", dist, "https://cdn.example")
+			if err != nil {
+				t.Fatalf("generateForType: %v", err)
+			}
+			pkg, ok := index["hud"]
+			if !ok {
+				t.Fatalf("index = %v, want hud", index)
+			}
+
+			if pkg.Latest != tt.latest {
+				t.Errorf("latest = %q, want %q", pkg.Latest, tt.latest)
+			}
+			if _, ok := pkg.Versions[pkg.Latest]; !ok {
+				t.Errorf("latest %q is not one of the indexed versions", pkg.Latest)
+			}
+
+			versions := []string{}
+			for version := range pkg.Versions {
+				versions = append(versions, version)
+			}
+			sort.Strings(versions)
+			want := []string{}
+			for version := range tt.versions {
+				want = append(want, version)
+			}
+			sort.Strings(want)
+			if !reflect.DeepEqual(versions, want) {
+				t.Fatalf("versions = %v, want %v", versions, want)
+			}
+
+			for version, info := range pkg.Versions {
+				if info.MoonLoader != tt.moonloader[version] {
+					t.Errorf("%s moonloader = %d, want %d", version, info.MoonLoader, tt.moonloader[version])
+				}
+				if !reflect.DeepEqual(info.Authors, tt.authors[version]) {
+					t.Errorf("%s authors = %v, want %v", version, info.Authors, tt.authors[version])
+				}
+				if url := "https://cdn.example/scripts/hud-" + version + ".zip"; info.URL != url {
+					t.Errorf("%s url = %q, want %q", version, info.URL, url)
+				}
+			}
+		})
+	}
+}
+
+func writePackage(t *testing.T, dist, id, version string, script *manifest.Script) {
+	t.Helper()
+
+	dir := filepath.Join(dist, "scripts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(dir, id+"-"+version+".zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(&manifest.Manifest{
+		ManifestVersion: manifest.CurrentManifestVersion,
+		ID:              id,
+		Version:         version,
+		Files:           map[string]manifest.FileInfo{id + ".lua": {}},
+		Script:          script,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("dep.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,10 +3,13 @@ package indexer
 import "github.com/Deps-Tech/deps-registry/tools/internal/manifest"
 
 type VersionInfo struct {
-	URL      string            `json:"url"`
-	SHA256   string            `json:"sha256"`
-	Size     int64             `json:"size"`
-	Manifest manifest.Manifest `json:"manifest"`
+	URL        string            `json:"url"`
+	SHA256     string            `json:"sha256"`
+	Size       int64             `json:"size"`
+	MoonLoader int               `json:"moonloader,omitempty"`
+	Authors    []string          `json:"authors,omitempty"`
+	Install    map[string]string `json:"install,omitempty"`
+	Manifest   manifest.Manifest `json:"manifest"`
 }
 
 type PackageInfo struct {
@@ -0,0 +1,127 @@
+package manifest
+
+import (
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type canonicalManifest struct {
+	plainManifest
+	Security *Security             `json:"security,omitempty"`
+	Native   map[string]NativeInfo `json:"native,omitempty"`
+	Metadata *Metadata             `json:"metadata,omitempty"`
+}
+
+type plainManifest Manifest
+
+func Canonical(m *Manifest) *Manifest {
+	c := *m
+
+	c.Provides = sortedStrings(m.Provides)
+
+	if m.Files != nil {
+		c.Files = make(map[string]FileInfo, len(m.Files))
+		for name, info := range m.Files {
+			c.Files[CanonicalPath(name)] = info
+		}
+	}
+
+	if m.Install != nil {
+		install := Install{Root: CanonicalPath(m.Install.Root)}
+		for file, destination := range m.Install.Files {
+			if install.Files == nil {
+				install.Files = make(map[string]string, len(m.Install.Files))
+			}
+			install.Files[CanonicalPath(file)] = CanonicalPath(destination)
+		}
+		c.Install = &install
+	}
+
+	for _, section := range []*map[string]string{
+		&c.Dependencies, &c.OptionalDependencies, &c.ScriptDependencies,
+		&c.PeerDependencies, &c.Conflicts, &c.Replaces,
+	} {
+		if len(*section) == 0 {
+			*section = nil
+		}
+	}
+
+	if m.Script != nil {
+		script := *m.Script
+		script.Properties = sortedStrings(script.Properties)
+		script.Dependencies = sortedStrings(script.Dependencies)
+		if len(script.Authors) == 0 {
+			script.Authors = nil
+		}
+		c.Script = &script
+		if reflect.DeepEqual(script, Script{}) {
+			c.Script = nil
+		}
+	}
+
+	c.Security.FileAccess = sortedStrings(m.Security.FileAccess)
+	c.Security.Capabilities = nil
+	for _, capability := range m.Security.Capabilities {
+		capability.File = CanonicalPath(capability.File)
+		c.Security.Capabilities = append(c.Security.Capabilities, capability)
+	}
+	sort.SliceStable(c.Security.Capabilities, func(i, j int) bool {
+		a, b := c.Security.Capabilities[i], c.Security.Capabilities[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Detail != b.Detail {
+			return a.Detail < b.Detail
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return !a.Dynamic && b.Dynamic
+	})
+
+	c.Native = nil
+	for name, info := range m.Native {
+		if c.Native == nil {
+			c.Native = make(map[string]NativeInfo, len(m.Native))
+		}
+		info.LuaOpen = sortedStrings(info.LuaOpen)
+		info.Imports = sortedStrings(info.Imports)
+		c.Native[CanonicalPath(name)] = info
+	}
+
+	c.Metadata.Tags = sortedStrings(m.Metadata.Tags)
+
+	return &c
+}
+
+func CanonicalPath(name string) string {
+	if name == "" {
+		return name
+	}
+	return path.Clean(strings.ReplaceAll(name, "\\", "/"))
+}
+
+func sortedStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func (c *canonicalManifest) fill(m *Manifest) {
+	c.plainManifest = plainManifest(*m)
+	c.Native = m.Native
+	if !reflect.DeepEqual(m.Security, Security{}) {
+		c.Security = &m.Security
+	}
+	if !reflect.DeepEqual(m.Metadata, Metadata{}) {
+		c.Metadata = &m.Metadata
+	}
+}
@@ -0,0 +1,214 @@
+package manifest
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCanonicalPath(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"lib\\mimgui\\init.lua", "lib/mimgui/init.lua"},
+		{"./lib//foo.lua", "lib/foo.lua"},
+		{"lib/../foo.lua", "foo.lua"},
+		{"[ARZ] CEF HUD Editor.lua", "[ARZ] CEF HUD Editor.lua"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalPath(tt.name); got != tt.want {
+				t.Errorf("CanonicalPath(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{
+			name:     "fixed key order and sorted maps",
+			manifest: `{"metadata": {"tags": ["ui", "imgui"]}, "version": "1.0.0", "dependencies": {"vkeys": "1.0.0", "ffi": "1.0.0"}, "id": "mimgui", "manifestVersion": "1.0", "files": {"b.lua": {"size": 2, "sha256": "` + testSHA + `"}, "a.lua": {"size": 1, "sha256": "` + testSHA + `"}}}`,
+			want: `{
+  "manifestVersion": "1.0",
+  "id": "mimgui",
+  "version": "1.0.0",
+  "files": {
+    "a.lua": {
+      "sha256": "` + testSHA + `",
+      "size": 1
+    },
+    "b.lua": {
+      "sha256": "` + testSHA + `",
+      "size": 2
+    }
+  },
+  "dependencies": {
+    "ffi": "1.0.0",
+    "vkeys": "1.0.0"
+  },
+  "metadata": {
+    "tags": [
+      "imgui",
+      "ui"
+    ]
+  }
+}
+`,
+		},
+		{
+			name:     "empty sections are omitted",
+			manifest: `{"manifestVersion": "1.0", "id": "inicfg", "version": "1.0.0", "files": {}, "dependencies": {}, "script": {"authors": []}, "security": {}, "metadata": {}}`,
+			want: `{
+  "manifestVersion": "1.0",
+  "id": "inicfg",
+  "version": "1.0.0",
+  "files": {}
+}
+`,
+		},
+		{
+			name:     "paths and capabilities",
+			manifest: `{"manifestVersion": "1.0", "id": "a", "version": "1.0.0", "files": {"lib\\a.lua": {"sha256": "` + testSHA + `", "size": 1}}, "security": {"capabilities": [{"kind": "network", "file": "lib\\a.lua", "line": 9}, {"kind": "ffi", "detail": "ffi", "file": "lib\\a.lua", "line": 1}, {"kind": "network", "file": "lib\\a.lua", "line": 2}]}}`,
+			want: `{
+  "manifestVersion": "1.0",
+  "id": "a",
+  "version": "1.0.0",
+  "files": {
+    "lib/a.lua": {
+      "sha256": "` + testSHA + `",
+      "size": 1
+    }
+  },
+  "security": {
+    "capabilities": [
+      {
+        "kind": "ffi",
+        "detail": "ffi",
+        "file": "lib/a.lua",
+        "line": 1
+      },
+      {
+        "kind": "network",
+        "file": "lib/a.lua",
+        "line": 2
+      },
+      {
+        "kind": "network",
+        "file": "lib/a.lua",
+        "line": 9
+      }
+    ]
+  }
+}
+`,
+		},
+		{
+			name:     "no HTML escaping",
+			manifest: `{"manifestVersion": "1.0", "id": "a", "version": "1.0.0", "files": {}, "metadata": {"sourceUrl": "https://example.com/?a=1&b=<2>"}}`,
+			want: `{
+  "manifestVersion": "1.0",
+  "id": "a",
+  "version": "1.0.0",
+  "files": {},
+  "metadata": {
+    "sourceUrl": "https://example.com/?a=1&b=<2>"
+  }
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.manifest))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got, err := Marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalRejectsUnsupportedFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest Manifest
+		want     string
+	}{
+		{
+			name:     "install in 1.0",
+			manifest: Manifest{ManifestVersion: Version1, Install: &Install{Root: RootLib}},
+			want:     "manifest version 1.0 does not support install, use 2.0",
+		},
+		{
+			name:     "range in 1.1",
+			manifest: Manifest{ManifestVersion: Version11, Dependencies: map[string]string{"ffi": "^1.0.0"}},
+			want:     `manifest version 1.1 does not support dependencies.ffi range "^1.0.0", use 2.0`,
+		},
+		{
+			name:     "metadata description in 2.0",
+			manifest: Manifest{ManifestVersion: Version2, Metadata: Metadata{Description: "d"}},
+			want:     "manifest version 2.0 does not support metadata.description, use description",
+		},
+		{
+			name:     "description in 1.0",
+			manifest: Manifest{ManifestVersion: Version1, Description: "d"},
+			want:     "manifest version 1.0 does not support description, use metadata.description",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Marshal(&tt.manifest)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryManifestsFormatIdempotently(t *testing.T) {
+	for _, path := range registryManifests(t) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := Parse(data)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		once, err := Marshal(m)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+
+		again, err := Parse(once)
+		if err != nil {
+			t.Errorf("%s: canonical form does not parse: %v", path, err)
+			continue
+		}
+		twice, _ := Marshal(again)
+		if string(once) != string(twice) {
+			t.Errorf("%s: formatting is not idempotent", path)
+		}
+		for _, err := range ValidateSchema(once) {
+			t.Errorf("%s: canonical form: %s", path, err)
+		}
+	}
+}
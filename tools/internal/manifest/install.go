@@ -0,0 +1,127 @@
+package manifest
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	RootMoonLoader = "moonloader"
+	RootLib        = "moonloader/lib"
+	RootConfig     = "moonloader/config"
+	RootResource   = "moonloader/resource"
+)
+
+var InstallRoots = []string{RootMoonLoader, RootLib, RootConfig, RootResource}
+
+var (
+	configExtensions   = []string{".ini", ".json", ".cfg"}
+	resourceExtensions = []string{".png", ".jpg", ".jpeg", ".bmp", ".gif", ".ttf", ".otf", ".wav", ".mp3", ".ogg"}
+	nativeExtensions   = []string{".dll", ".so"}
+)
+
+func InstallRootOf(destination string) string {
+	root := ""
+	for _, known := range InstallRoots {
+		if destination != known && !strings.HasPrefix(destination, known+"/") {
+			continue
+		}
+		if len(known) > len(root) {
+			root = known
+		}
+	}
+	return root
+}
+
+func DefaultInstall(itemType, id string, files []string) *Install {
+	canonical := make([]string, 0, len(files))
+	for _, file := range files {
+		canonical = append(canonical, CanonicalPath(file))
+	}
+	files = canonical
+
+	if itemType != "scripts" {
+		return &Install{Root: libraryRoot(id, files)}
+	}
+
+	install := &Install{Root: RootMoonLoader}
+	for _, file := range files {
+		if strings.Contains(file, "/") {
+			continue
+		}
+
+		root := RootMoonLoader
+		switch ext := strings.ToLower(path.Ext(file)); {
+		case containsString(nativeExtensions, ext):
+			root = RootLib
+		case containsString(configExtensions, ext):
+			root = RootConfig
+		case containsString(resourceExtensions, ext):
+			root = RootResource
+		}
+		if root == install.Root {
+			continue
+		}
+		if install.Files == nil {
+			install.Files = make(map[string]string)
+		}
+		install.Files[file] = root + "/" + file
+	}
+
+	return install
+}
+
+func libraryRoot(id string, files []string) string {
+	if len(files) == 1 {
+		return RootLib
+	}
+	for _, file := range files {
+		if strings.Contains(file, "/") {
+			continue
+		}
+		if moduleKey(strings.TrimSuffix(file, path.Ext(file))) == moduleKey(id) {
+			return RootLib
+		}
+	}
+	return RootLib + "/" + id
+}
+
+func moduleKey(name string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+}
+
+func (i *Install) Destination(file string) string {
+	if destination, ok := i.Files[file]; ok {
+		return destination
+	}
+	return i.Root + "/" + file
+}
+
+func (m *Manifest) InstallLayout(itemType string) map[string]string {
+	files := make([]string, 0, len(m.Files))
+	for file := range m.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	install := m.Install
+	if install == nil {
+		install = DefaultInstall(itemType, m.ID, files)
+	}
+
+	layout := make(map[string]string, len(files))
+	for _, file := range files {
+		layout[file] = install.Destination(file)
+	}
+	return layout
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultInstall(t *testing.T) {
+	tests := []struct {
+		name     string
+		itemType string
+		id       string
+		files    []string
+		want     *Install
+	}{
+		{
+			name:     "single file dependency",
+			itemType: "deps",
+			id:       "vkeys",
+			files:    []string{"vkeys.lua"},
+			want:     &Install{Root: RootLib},
+		},
+		{
+			name:     "dependency with a matching entry file",
+			itemType: "deps",
+			id:       "sa-mp api",
+			files:    []string{"sampapi.lua", "sampapi/core.lua"},
+			want:     &Install{Root: RootLib},
+		},
+		{
+			name:     "dependency without an entry file",
+			itemType: "deps",
+			id:       "mimgui",
+			files:    []string{"init.lua", "imgui.lua", "cimguidx9.dll"},
+			want:     &Install{Root: RootLib + "/mimgui"},
+		},
+		{
+			name:     "script with top-level assets",
+			itemType: "scripts",
+			id:       "hud",
+			files:    []string{"hud.lua", "hud.ini", "logo.png", "helper.dll"},
+			want: &Install{Root: RootMoonLoader, Files: map[string]string{
+				"hud.ini":    RootConfig + "/hud.ini",
+				"logo.png":   RootResource + "/logo.png",
+				"helper.dll": RootLib + "/helper.dll",
+			}},
+		},
+		{
+			name:     "script files already in subdirectories",
+			itemType: "scripts",
+			id:       "hud",
+			files:    []string{"hud.lua", "config/hud.ini", "resource/hud/logo.png", "lib/helper.dll", "images/bg.png"},
+			want:     &Install{Root: RootMoonLoader},
+		},
+		{
+			name:     "windows separators",
+			itemType: "scripts",
+			id:       "hud",
+			files:    []string{"hud.lua", "config\\hud.ini"},
+			want:     &Install{Root: RootMoonLoader},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultInstall(tt.itemType, tt.id, tt.files)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DefaultInstall = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallLayout(t *testing.T) {
+	m := &Manifest{
+		ID: "hud",
+		Files: map[string]FileInfo{
+			"hud.lua":        {},
+			"hud.ini":        {},
+			"config/old.ini": {},
+		},
+	}
+
+	want := map[string]string{
+		"hud.lua":        RootMoonLoader + "/hud.lua",
+		"hud.ini":        RootConfig + "/hud.ini",
+		"config/old.ini": RootConfig + "/old.ini",
+	}
+	if got := m.InstallLayout("scripts"); !reflect.DeepEqual(got, want) {
+		t.Errorf("InstallLayout = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,281 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+type nodeKind string
+
+const (
+	kindObject  nodeKind = "object"
+	kindArray   nodeKind = "array"
+	kindString  nodeKind = "string"
+	kindNumber  nodeKind = "number"
+	kindBoolean nodeKind = "boolean"
+	kindNull    nodeKind = "null"
+)
+
+type jsonNode struct {
+	Kind    nodeKind
+	Offset  int
+	Members []jsonMember
+	Items   []*jsonNode
+	String  string
+	Number  float64
+	Integer bool
+	Bool    bool
+}
+
+type jsonMember struct {
+	Key    string
+	Offset int
+	Value  *jsonNode
+}
+
+func (n *jsonNode) member(key string) *jsonNode {
+	for _, m := range n.Members {
+		if m.Key == key {
+			return m.Value
+		}
+	}
+	return nil
+}
+
+func (n *jsonNode) value() any {
+	switch n.Kind {
+	case kindString:
+		return n.String
+	case kindNumber:
+		return n.Number
+	case kindBoolean:
+		return n.Bool
+	}
+	return nil
+}
+
+type jsonSyntaxError struct {
+	Offset  int
+	Message string
+}
+
+func (e *jsonSyntaxError) Error() string {
+	return e.Message
+}
+
+type jsonReader struct {
+	data []byte
+	pos  int
+}
+
+func parseJSON(data []byte) (*jsonNode, error) {
+	r := &jsonReader{data: data}
+	node, err := r.value()
+	if err != nil {
+		return nil, err
+	}
+	r.skipSpace()
+	if r.pos < len(r.data) {
+		return nil, r.errorf("unexpected %q after top-level value", r.data[r.pos])
+	}
+	return node, nil
+}
+
+func (r *jsonReader) errorf(format string, args ...any) error {
+	return &jsonSyntaxError{Offset: r.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (r *jsonReader) skipSpace() {
+	for r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case ' ', '\t', '\n', '\r':
+			r.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (r *jsonReader) value() (*jsonNode, error) {
+	r.skipSpace()
+	if r.pos >= len(r.data) {
+		return nil, r.errorf("unexpected end of input")
+	}
+
+	start := r.pos
+	switch c := r.data[r.pos]; {
+	case c == '{':
+		return r.object()
+	case c == '[':
+		return r.array()
+	case c == '"':
+		s, err := r.string()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonNode{Kind: kindString, Offset: start, String: s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return r.number()
+	default:
+		for _, literal := range []struct {
+			text string
+			node jsonNode
+		}{
+			{"true", jsonNode{Kind: kindBoolean, Bool: true}},
+			{"false", jsonNode{Kind: kindBoolean}},
+			{"null", jsonNode{Kind: kindNull}},
+		} {
+			if len(r.data)-r.pos >= len(literal.text) && string(r.data[r.pos:r.pos+len(literal.text)]) == literal.text {
+				r.pos += len(literal.text)
+				node := literal.node
+				node.Offset = start
+				return &node, nil
+			}
+		}
+		return nil, r.errorf("unexpected %q", c)
+	}
+}
+
+func (r *jsonReader) object() (*jsonNode, error) {
+	node := &jsonNode{Kind: kindObject, Offset: r.pos, Members: []jsonMember{}}
+	r.pos++
+
+	r.skipSpace()
+	if r.pos < len(r.data) && r.data[r.pos] == '}' {
+		r.pos++
+		return node, nil
+	}
+
+	for {
+		r.skipSpace()
+		if r.pos >= len(r.data) || r.data[r.pos] != '"' {
+			return nil, r.errorf("expected object key")
+		}
+		keyOffset := r.pos
+		key, err := r.string()
+		if err != nil {
+			return nil, err
+		}
+		if node.member(key) != nil {
+			r.pos = keyOffset
+			return nil, r.errorf("duplicate key %q", key)
+		}
+
+		r.skipSpace()
+		if r.pos >= len(r.data) || r.data[r.pos] != ':' {
+			return nil, r.errorf("expected ':' after object key")
+		}
+		r.pos++
+
+		value, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		node.Members = append(node.Members, jsonMember{Key: key, Offset: keyOffset, Value: value})
+
+		r.skipSpace()
+		if r.pos >= len(r.data) {
+			return nil, r.errorf("unexpected end of input")
+		}
+		switch r.data[r.pos] {
+		case ',':
+			r.pos++
+		case '}':
+			r.pos++
+			return node, nil
+		default:
+			return nil, r.errorf("expected ',' or '}' in object")
+		}
+	}
+}
+
+func (r *jsonReader) array() (*jsonNode, error) {
+	node := &jsonNode{Kind: kindArray, Offset: r.pos, Items: []*jsonNode{}}
+	r.pos++
+
+	r.skipSpace()
+	if r.pos < len(r.data) && r.data[r.pos] == ']' {
+		r.pos++
+		return node, nil
+	}
+
+	for {
+		item, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+		node.Items = append(node.Items, item)
+
+		r.skipSpace()
+		if r.pos >= len(r.data) {
+			return nil, r.errorf("unexpected end of input")
+		}
+		switch r.data[r.pos] {
+		case ',':
+			r.pos++
+		case ']':
+			r.pos++
+			return node, nil
+		default:
+			return nil, r.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (r *jsonReader) string() (string, error) {
+	start := r.pos
+	r.pos++
+	for r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case '\\':
+			r.pos += 2
+		case '"':
+			r.pos++
+			var s string
+			if err := json.Unmarshal(r.data[start:r.pos], &s); err != nil {
+				r.pos = start
+				return "", r.errorf("invalid string literal")
+			}
+			return s, nil
+		default:
+			r.pos++
+		}
+	}
+	r.pos = start
+	return "", r.errorf("unterminated string")
+}
+
+func (r *jsonReader) number() (*jsonNode, error) {
+	start := r.pos
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		if !(c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E' || (c >= '0' && c <= '9')) {
+			break
+		}
+		r.pos++
+	}
+
+	text := string(r.data[start:r.pos])
+	if !json.Valid(r.data[start:r.pos]) {
+		r.pos = start
+		return nil, r.errorf("invalid number %s", text)
+	}
+	number, _ := strconv.ParseFloat(text, 64)
+
+	return &jsonNode{Kind: kindNumber, Offset: start, Number: number, Integer: number == math.Trunc(number)}, nil
+}
+
+func lineColumn(data []byte, offset int) (int, int) {
+	line, column := 1, 1
+	for i := 0; i < offset && i < len(data); i++ {
+		if data[i] == '\n' {
+			line++
+			column = 1
+		} else if data[i]&0xC0 != 0x80 {
+			column++
+		}
+	}
+	return line, column
+}
@@ -1,6 +1,7 @@
 package manifest
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -12,19 +13,45 @@ func Load(path string) (*Manifest, error) {
 		return nil, err
 	}
 
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Manifest, error) {
 	var m Manifest
 	if err := json.Unmarshal(data, &m); err != nil {
 		return nil, err
 	}
+	if err := checkVersion(&m); err != nil {
+		return nil, err
+	}
 
 	return &m, nil
 }
 
 func Save(path string, m *Manifest) error {
-	data, err := json.MarshalIndent(m, "", "  ")
+	data, err := Marshal(m)
 	if err != nil {
 		return err
 	}
 
 	return os.WriteFile(filepath.Join(path, "dep.json"), data, 0644)
 }
+
+func Marshal(m *Manifest) ([]byte, error) {
+	if err := checkVersion(m); err != nil {
+		return nil, err
+	}
+
+	var c canonicalManifest
+	c.fill(Canonical(m))
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(&c); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,130 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/versioning"
+)
+
+type Migration struct {
+	From        string
+	To          string
+	Description string
+	Apply       func(raw map[string]any) (map[string]any, error)
+}
+
+var migrations = map[string]Migration{}
+
+func RegisterMigration(m Migration) {
+	if _, exists := migrations[m.From]; exists {
+		panic("manifest: duplicate migration from " + m.From)
+	}
+	migrations[m.From] = m
+}
+
+func init() {
+	RegisterMigration(Migration{
+		From:        Version1,
+		To:          Version11,
+		Description: "move metadata.description to the top-level description",
+		Apply:       migrateMetadataDescription,
+	})
+	RegisterMigration(Migration{
+		From:        Version11,
+		To:          Version2,
+		Description: "turn exact dependency versions into caret ranges",
+		Apply:       migrateDependencyRanges,
+	})
+}
+
+func MigrationPath(from, to string) ([]Migration, error) {
+	if versioning.Compare(from, to) > 0 {
+		return nil, fmt.Errorf("cannot downgrade manifest from %s to %s", from, to)
+	}
+
+	path := []Migration{}
+	for version := from; version != to; {
+		m, ok := migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration path from %s to %s", from, to)
+		}
+		path = append(path, m)
+		version = m.To
+	}
+
+	return path, nil
+}
+
+func Migrate(data []byte, to string) (map[string]any, []Migration, error) {
+	raw, err := decodeRaw(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	from, _ := raw["manifestVersion"].(string)
+	if from == "" {
+		return nil, nil, fmt.Errorf("legacy manifest without manifestVersion")
+	}
+
+	path, err := MigrationPath(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, m := range path {
+		copied, _ := json.Marshal(raw)
+		raw, _ = decodeRaw(copied)
+		raw, err = m.Apply(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s -> %s: %w", m.From, m.To, err)
+		}
+		raw["manifestVersion"] = m.To
+	}
+
+	return raw, path, nil
+}
+
+func decodeRaw(data []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func migrateMetadataDescription(raw map[string]any) (map[string]any, error) {
+	metadata, _ := raw["metadata"].(map[string]any)
+	description, ok := metadata["description"].(string)
+	if !ok {
+		return raw, nil
+	}
+
+	if existing, _ := raw["description"].(string); existing != "" && existing != description {
+		return nil, fmt.Errorf("metadata.description %q conflicts with description %q", description, existing)
+	}
+
+	raw["description"] = description
+	delete(metadata, "description")
+
+	return raw, nil
+}
+
+func migrateDependencyRanges(raw map[string]any) (map[string]any, error) {
+	for _, section := range []string{"dependencies", "optionalDependencies", "scriptDependencies"} {
+		deps, _ := raw[section].(map[string]any)
+		for id, value := range deps {
+			version, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s.%s: expected a version string", section, id)
+			}
+			deps[id] = versioning.CaretRange(version)
+		}
+	}
+
+	return raw, nil
+}
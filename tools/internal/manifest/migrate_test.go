@@ -0,0 +1,235 @@
+package manifest
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMigrationPath(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		want    []string
+		wantErr string
+	}{
+		{from: Version1, to: Version2, want: []string{"1.0->1.1", "1.1->2.0"}},
+		{from: Version11, to: Version2, want: []string{"1.1->2.0"}},
+		{from: Version2, to: Version2, want: []string{}},
+		{from: Version2, to: Version1, wantErr: "cannot downgrade manifest from 2.0 to 1.0"},
+		{from: "0.9", to: Version2, wantErr: "no migration path from 0.9 to 2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			path, err := MigrationPath(tt.from, tt.to)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MigrationPath: %v", err)
+			}
+
+			got := []string{}
+			for _, m := range path {
+				got = append(got, m.From+"->"+m.To)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("path = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		to       string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "metadata description moves to the top level",
+			manifest: `{"manifestVersion": "1.0", "id": "inicfg", "metadata": {"description": "ini files", "tags": ["builtin"]}}`,
+			to:       Version11,
+			want:     `{"manifestVersion": "1.1", "id": "inicfg", "description": "ini files", "metadata": {"tags": ["builtin"]}}`,
+		},
+		{
+			name:     "script section is left alone",
+			manifest: `{"manifestVersion": "1.0", "id": "a", "script": {"description": "from script_description"}, "metadata": {"description": "from metadata"}}`,
+			to:       Version11,
+			want:     `{"manifestVersion": "1.1", "id": "a", "description": "from metadata", "script": {"description": "from script_description"}, "metadata": {}}`,
+		},
+		{
+			name:     "conflicting descriptions",
+			manifest: `{"manifestVersion": "1.0", "id": "a", "description": "one", "metadata": {"description": "two"}}`,
+			to:       Version11,
+			wantErr:  `1.0 -> 1.1: metadata.description "two" conflicts with description "one"`,
+		},
+		{
+			name:     "exact versions become caret ranges",
+			manifest: `{"manifestVersion": "1.1", "id": "a", "dependencies": {"mimgui": "1.0.0", "lfs": "*"}, "optionalDependencies": {"cjson": "2.1.0"}, "scriptDependencies": {"hud": "1.2"}}`,
+			to:       Version2,
+			want:     `{"manifestVersion": "2.0", "id": "a", "dependencies": {"mimgui": "^1.0.0", "lfs": "*"}, "optionalDependencies": {"cjson": "^2.1.0"}, "scriptDependencies": {"hud": "^1.2"}}`,
+		},
+		{
+			name:     "non-string dependency version",
+			manifest: `{"manifestVersion": "1.1", "id": "a", "dependencies": {"mimgui": 1}}`,
+			to:       Version2,
+			wantErr:  "1.1 -> 2.0: dependencies.mimgui: expected a version string",
+		},
+		{
+			name:     "full chain",
+			manifest: `{"manifestVersion": "1.0", "id": "a", "dependencies": {"mimgui": "1.0.0"}, "metadata": {"description": "d"}}`,
+			to:       Version2,
+			want:     `{"manifestVersion": "2.0", "id": "a", "description": "d", "dependencies": {"mimgui": "^1.0.0"}, "metadata": {}}`,
+		},
+		{
+			name:     "legacy manifest",
+			manifest: `{"id": "a"}`,
+			to:       Version2,
+			wantErr:  "legacy manifest without manifestVersion",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _, err := Migrate([]byte(tt.manifest), tt.to)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+
+			got, _ := json.Marshal(raw)
+			var gotValue, wantValue any
+			json.Unmarshal(got, &gotValue)
+			json.Unmarshal([]byte(tt.want), &wantValue)
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("migrated = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryManifestsMigrateStepByStep(t *testing.T) {
+	for _, path := range registryManifests(t) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var header struct {
+			ManifestVersion string `json:"manifestVersion"`
+		}
+		json.Unmarshal(data, &header)
+		steps, err := MigrationPath(header.ManifestVersion, CurrentManifestVersion)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+
+		original := data
+		for _, step := range steps {
+			name := path + " (" + step.From + " -> " + step.To + ")"
+
+			raw, applied, err := Migrate(data, step.To)
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+				break
+			}
+			if len(applied) != 1 {
+				t.Errorf("%s: applied %d migrations, want 1", name, len(applied))
+			}
+			migrated, _ := json.Marshal(raw)
+
+			for _, err := range ValidateSchema(migrated) {
+				t.Errorf("%s: %s", name, err)
+			}
+
+			m, err := Parse(migrated)
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+				break
+			}
+			if roundTrip, _ := json.Marshal(m); !sameJSON(migrated, roundTrip) {
+				t.Errorf("%s: fields were lost converting to a manifest:\n%s\n%s", name, migrated, roundTrip)
+			}
+			if _, err := Marshal(m); err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+
+			again, applied, err := Migrate(migrated, step.To)
+			if err != nil || len(applied) != 0 {
+				t.Errorf("%s: migrating again applied %d migrations (%v)", name, len(applied), err)
+			} else if againData, _ := json.Marshal(again); !sameJSON(againData, migrated) {
+				t.Errorf("%s: migrating again changed the manifest", name)
+			}
+
+			var before, after map[string]any
+			json.Unmarshal(original, &before)
+			json.Unmarshal(migrated, &after)
+			for _, key := range []string{"id", "version", "files"} {
+				if !reflect.DeepEqual(before[key], after[key]) {
+					t.Errorf("%s: %s changed", name, key)
+				}
+			}
+
+			data = migrated
+		}
+	}
+}
+
+func sameJSON(a, b []byte) bool {
+	var left, right any
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(pruneEmpty(left), pruneEmpty(right))
+}
+
+func pruneEmpty(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			item = pruneEmpty(item)
+			if isEmptyJSON(item) {
+				delete(v, key)
+				continue
+			}
+			v[key] = item
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = pruneEmpty(item)
+		}
+		return v
+	}
+	return value
+}
+
+func isEmptyJSON(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
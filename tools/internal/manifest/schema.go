@@ -0,0 +1,284 @@
+package manifest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed schema/*.schema.json
+var schemaFiles embed.FS
+
+type SchemaError struct {
+	Pointer string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, pointer, e.Message)
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Defs                 map[string]*schema `json:"$defs"`
+	Type                 string             `json:"type"`
+	Const                any                `json:"const"`
+	Enum                 []any              `json:"enum"`
+	Pattern              string             `json:"pattern"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	MinProperties        *int               `json:"minProperties"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	PropertyNames        *schema            `json:"propertyNames"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+
+	forbidden bool
+	pattern   *regexp.Regexp
+}
+
+func (s *schema) UnmarshalJSON(data []byte) error {
+	var allowed bool
+	if err := json.Unmarshal(data, &allowed); err == nil {
+		s.forbidden = !allowed
+		return nil
+	}
+
+	type plain schema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+func SchemaFileName(version string) string {
+	return "dep-" + version + ".schema.json"
+}
+
+func SchemaVersions() []string {
+	entries, _ := schemaFiles.ReadDir("schema")
+	versions := []string{}
+	for _, entry := range entries {
+		name := strings.TrimPrefix(entry.Name(), "dep-")
+		versions = append(versions, strings.TrimSuffix(name, ".schema.json"))
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func Schema(version string) ([]byte, error) {
+	data, err := schemaFiles.ReadFile("schema/" + SchemaFileName(version))
+	if err != nil {
+		return nil, fmt.Errorf("no schema for manifest version %q", version)
+	}
+	return data, nil
+}
+
+func loadSchema(version string) (*schema, error) {
+	data, err := Schema(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var root schema
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("schema %s: %w", SchemaFileName(version), err)
+	}
+	return &root, nil
+}
+
+func ValidateSchema(data []byte) []*SchemaError {
+	v := &schemaValidator{data: data, errors: []*SchemaError{}}
+
+	doc, err := parseJSON(data)
+	if err != nil {
+		offset := 0
+		if syntaxErr, ok := err.(*jsonSyntaxError); ok {
+			offset = syntaxErr.Offset
+		}
+		v.report("", offset, "invalid JSON: %s", err.Error())
+		return v.errors
+	}
+
+	version := CurrentManifestVersion
+	if node := doc.member("manifestVersion"); node != nil && node.Kind == kindString {
+		version = node.String
+	}
+
+	root, err := loadSchema(version)
+	if err != nil {
+		v.report("/manifestVersion", doc.member("manifestVersion").Offset, "unsupported manifest version %q (known: %s)", version, strings.Join(SchemaVersions(), ", "))
+		return v.errors
+	}
+
+	v.root = root
+	v.validate(root, doc, "")
+	return v.errors
+}
+
+type schemaValidator struct {
+	data   []byte
+	root   *schema
+	errors []*SchemaError
+}
+
+func (v *schemaValidator) report(pointer string, offset int, format string, args ...any) {
+	line, column := lineColumn(v.data, offset)
+	v.errors = append(v.errors, &SchemaError{
+		Pointer: pointer,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *schemaValidator) resolve(s *schema) *schema {
+	for s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+		def := v.root.Defs[name]
+		if !ok || def == nil {
+			panic("manifest schema: unresolvable $ref " + s.Ref)
+		}
+		s = def
+	}
+	return s
+}
+
+func (v *schemaValidator) validate(s *schema, node *jsonNode, pointer string) {
+	s = v.resolve(s)
+
+	if s.forbidden {
+		v.report(pointer, node.Offset, "not allowed")
+		return
+	}
+
+	if s.Type != "" && !matchesType(s.Type, node) {
+		v.report(pointer, node.Offset, "expected %s, got %s", s.Type, describeKind(node))
+		return
+	}
+
+	if s.Const != nil && !sameValue(s.Const, node) {
+		v.report(pointer, node.Offset, "must be %s", formatValue(s.Const))
+	}
+
+	if s.Enum != nil {
+		matched := false
+		for _, allowed := range s.Enum {
+			if sameValue(allowed, node) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			options := []string{}
+			for _, allowed := range s.Enum {
+				options = append(options, formatValue(allowed))
+			}
+			v.report(pointer, node.Offset, "must be one of %s", strings.Join(options, ", "))
+		}
+	}
+
+	switch node.Kind {
+	case kindString:
+		if s.MinLength != nil && len([]rune(node.String)) < *s.MinLength {
+			v.report(pointer, node.Offset, "must be at least %d characters", *s.MinLength)
+		}
+		if s.Pattern != "" {
+			if s.pattern == nil {
+				s.pattern = regexp.MustCompile(s.Pattern)
+			}
+			if !s.pattern.MatchString(node.String) {
+				v.report(pointer, node.Offset, "%q does not match %s", node.String, s.Pattern)
+			}
+		}
+	case kindNumber:
+		if s.Minimum != nil && node.Number < *s.Minimum {
+			v.report(pointer, node.Offset, "must be at least %v", *s.Minimum)
+		}
+	case kindArray:
+		if s.Items != nil {
+			for i, item := range node.Items {
+				v.validate(s.Items, item, fmt.Sprintf("%s/%d", pointer, i))
+			}
+		}
+	case kindObject:
+		v.validateObject(s, node, pointer)
+	}
+}
+
+func (v *schemaValidator) validateObject(s *schema, node *jsonNode, pointer string) {
+	if s.MinProperties != nil && len(node.Members) < *s.MinProperties {
+		if *s.MinProperties == 1 {
+			v.report(pointer, node.Offset, "must not be empty")
+		} else {
+			v.report(pointer, node.Offset, "must have at least %d entries", *s.MinProperties)
+		}
+	}
+
+	for _, key := range s.Required {
+		if node.member(key) == nil {
+			v.report(pointer, node.Offset, "missing required key %q", key)
+		}
+	}
+
+	for _, member := range node.Members {
+		memberPointer := pointer + "/" + escapePointer(member.Key)
+		if s.PropertyNames != nil {
+			v.validate(s.PropertyNames, &jsonNode{Kind: kindString, Offset: member.Offset, String: member.Key}, memberPointer)
+		}
+		if property, ok := s.Properties[member.Key]; ok {
+			v.validate(property, member.Value, memberPointer)
+			continue
+		}
+		if s.AdditionalProperties == nil {
+			continue
+		}
+		if s.AdditionalProperties.forbidden {
+			v.report(memberPointer, member.Offset, "unknown key %q", member.Key)
+			continue
+		}
+		v.validate(s.AdditionalProperties, member.Value, memberPointer)
+	}
+}
+
+func matchesType(typ string, node *jsonNode) bool {
+	switch typ {
+	case "integer":
+		return node.Kind == kindNumber && node.Integer
+	case "number":
+		return node.Kind == kindNumber
+	default:
+		return string(node.Kind) == typ
+	}
+}
+
+func describeKind(node *jsonNode) string {
+	if node.Kind == kindNumber && !node.Integer {
+		return "non-integer number"
+	}
+	return string(node.Kind)
+}
+
+func sameValue(expected any, node *jsonNode) bool {
+	switch node.Kind {
+	case kindObject, kindArray:
+		return false
+	}
+	return expected == node.value()
+}
+
+func formatValue(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,116 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSHA = "718a8ab7c65b8333b4b849e0c2df1413dc9334057839d68704e046918510a815"
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name:     "minimal 1.0",
+			manifest: `{"manifestVersion": "1.0", "id": "lfs", "version": "1.0.0", "files": {"lfs.dll": {"sha256": "` + testSHA + `", "size": 1}}}`,
+		},
+		{
+			name:     "builtin without files",
+			manifest: `{"manifestVersion": "1.0", "id": "inicfg", "version": "1.0.0", "files": {}}`,
+		},
+		{
+			name:     "1.0 metadata keys from the existing registry",
+			manifest: `{"manifestVersion": "1.0", "id": "inicfg", "version": "1.0.0", "files": {}, "metadata": {"description": "ini files", "uploadedBy": "DepsCian"}}`,
+		},
+		{
+			name:     "metadata description moved out in 1.1",
+			manifest: `{"manifestVersion": "1.1", "id": "inicfg", "version": "1.0.0", "files": {}, "metadata": {"description": "ini files"}}`,
+			want:     []string{`1:90: /metadata/description: unknown key "description"`},
+		},
+		{
+			name:     "top-level description from 1.1",
+			manifest: `{"manifestVersion": "2.0", "id": "inicfg", "version": "1.0.0", "description": "ini files", "files": {}}`,
+		},
+		{
+			name:     "top-level description in 1.0",
+			manifest: `{"manifestVersion": "1.0", "id": "inicfg", "version": "1.0.0", "description": "ini files", "files": {}}`,
+			want:     []string{`1:64: /description: unknown key "description"`},
+		},
+		{
+			name:     "missing required keys",
+			manifest: `{"manifestVersion": "2.0", "id": "lfs"}`,
+			want:     []string{`1:1: /: missing required key "version"`, `1:1: /: missing required key "files"`},
+		},
+		{
+			name:     "bad sha256",
+			manifest: `{"manifestVersion": "1.0", "id": "lfs", "version": "1.0.0", "files": {"lfs.dll": {"sha256": "abc", "size": 1}}}`,
+			want:     []string{`1:93: /files/lfs.dll/sha256: "abc" does not match ^[0-9a-f]{64}$`},
+		},
+		{
+			name:     "install outside moonloader",
+			manifest: `{"manifestVersion": "2.0", "id": "lfs", "version": "1.0.0", "files": {}, "install": {"root": "C:/Windows"}}`,
+			want:     []string{`1:94: /install/root: "C:/Windows" does not match ^moonloader(/[^\\:]+)?$`},
+		},
+		{
+			name:     "unsupported version",
+			manifest: `{"manifestVersion": "9.9", "id": "lfs"}`,
+			want:     []string{`1:21: /manifestVersion: unsupported manifest version "9.9" (known: 1.0, 1.1, 2.0)`},
+		},
+		{
+			name:     "invalid JSON",
+			manifest: "{\n  \"id\": \"lfs\",\n}",
+			want:     []string{`3:1: /: invalid JSON: `},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSchema([]byte(tt.manifest))
+			if len(errs) != len(tt.want) {
+				t.Fatalf("got %d errors %v, want %d %v", len(errs), errs, len(tt.want), tt.want)
+			}
+			for i, err := range errs {
+				if !strings.HasPrefix(err.Error(), tt.want[i]) {
+					t.Errorf("error %d = %q, want prefix %q", i, err.Error(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryManifestsMatchSchema(t *testing.T) {
+	paths := registryManifests(t)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, err := range ValidateSchema(data) {
+			t.Errorf("%s:%s", path, err)
+		}
+	}
+}
+
+func registryManifests(t *testing.T) []string {
+	t.Helper()
+
+	paths := []string{}
+	for _, root := range []string{"deps", "scripts"} {
+		matches, err := filepath.Glob(filepath.Join("..", "..", "..", root, "*", "*", "dep.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no dep.json files found in the registry")
+	}
+
+	return paths
+}
@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/versioning"
+)
+
+const (
+	Version1               = "1.0"
+	Version11              = "1.1"
+	Version2               = "2.0"
+	CurrentManifestVersion = Version2
+)
+
+func (m *Manifest) IsV2() bool {
+	return m.ManifestVersion == Version2
+}
+
+func (m *Manifest) DependencyRanges() map[string]map[string]string {
+	return map[string]map[string]string{
+		"dependencies":         m.Dependencies,
+		"optionalDependencies": m.OptionalDependencies,
+		"scriptDependencies":   m.ScriptDependencies,
+		"peerDependencies":     m.PeerDependencies,
+		"conflicts":            m.Conflicts,
+		"replaces":             m.Replaces,
+	}
+}
+
+func checkVersion(m *Manifest) error {
+	if m.Metadata.Description != "" && m.ManifestVersion != Version1 && m.ManifestVersion != "" {
+		return fmt.Errorf("manifest version %s does not support metadata.description, use description", m.ManifestVersion)
+	}
+	if m.Description != "" && (m.ManifestVersion == Version1 || m.ManifestVersion == "") {
+		return fmt.Errorf("manifest version %s does not support description, use metadata.description", Version1)
+	}
+
+	switch m.ManifestVersion {
+	case Version2:
+		return nil
+	case Version1, Version11, "":
+	default:
+		return fmt.Errorf("unsupported manifest version %q", m.ManifestVersion)
+	}
+
+	unsupported := []string{}
+	if m.Install != nil {
+		unsupported = append(unsupported, "install")
+	}
+	for section, entries := range m.DependencyRanges() {
+		for id, constraint := range entries {
+			if section == "peerDependencies" || section == "conflicts" || section == "replaces" {
+				unsupported = append(unsupported, section)
+				break
+			}
+			if !versioning.IsExact(constraint) {
+				unsupported = append(unsupported, fmt.Sprintf("%s.%s range %q", section, id, constraint))
+			}
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return fmt.Errorf("manifest version %s does not support %s, use %s", m.ManifestVersion, strings.Join(unsupported, ", "), Version2)
+	}
+
+	return nil
+}
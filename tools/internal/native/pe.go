@@ -0,0 +1,209 @@
+package native
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	MachineX86   = "x86"
+	MachineX64   = "x64"
+	MachineARM   = "arm"
+	MachineARM64 = "arm64"
+)
+
+type Info struct {
+	Machine string
+	Exports []string
+	LuaOpen []string
+	Imports []string
+}
+
+func Inspect(path string) (*Info, error) {
+	f, err := pe.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PE file: %w", err)
+	}
+	defer f.Close()
+
+	info := &Info{
+		Machine: machineName(f.Machine),
+		Exports: []string{},
+		LuaOpen: []string{},
+		Imports: []string{},
+	}
+
+	exports, err := exportedNames(f)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(exports)
+	info.Exports = exports
+
+	for _, name := range exports {
+		if strings.HasPrefix(name, "luaopen_") {
+			info.LuaOpen = append(info.LuaOpen, name)
+		}
+	}
+
+	symbols, err := f.ImportedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read imports: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, symbol := range symbols {
+		idx := strings.LastIndex(symbol, ":")
+		if idx < 0 {
+			continue
+		}
+		lib := strings.ToLower(symbol[idx+1:])
+		if !seen[lib] {
+			seen[lib] = true
+			info.Imports = append(info.Imports, lib)
+		}
+	}
+	sort.Strings(info.Imports)
+
+	return info, nil
+}
+
+func LuaOpenSymbol(module string) string {
+	if idx := strings.Index(module, "-"); idx >= 0 {
+		module = module[idx+1:]
+	}
+	return "luaopen_" + strings.ReplaceAll(module, ".", "_")
+}
+
+func machineName(machine uint16) string {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return MachineX86
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return MachineX64
+	case pe.IMAGE_FILE_MACHINE_ARM, pe.IMAGE_FILE_MACHINE_ARMNT:
+		return MachineARM
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return MachineARM64
+	default:
+		return fmt.Sprintf("unknown(0x%x)", machine)
+	}
+}
+
+type exportDirectory struct {
+	Characteristics       uint32
+	TimeDateStamp         uint32
+	MajorVersion          uint16
+	MinorVersion          uint16
+	Name                  uint32
+	Base                  uint32
+	NumberOfFunctions     uint32
+	NumberOfNames         uint32
+	AddressOfFunctions    uint32
+	AddressOfNames        uint32
+	AddressOfNameOrdinals uint32
+}
+
+func exportedNames(f *pe.File) ([]string, error) {
+	var dir pe.DataDirectory
+
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		if oh.NumberOfRvaAndSizes <= pe.IMAGE_DIRECTORY_ENTRY_EXPORT {
+			return []string{}, nil
+		}
+		dir = oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_EXPORT]
+	case *pe.OptionalHeader64:
+		if oh.NumberOfRvaAndSizes <= pe.IMAGE_DIRECTORY_ENTRY_EXPORT {
+			return []string{}, nil
+		}
+		dir = oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_EXPORT]
+	default:
+		return []string{}, nil
+	}
+
+	if dir.VirtualAddress == 0 || dir.Size == 0 {
+		return []string{}, nil
+	}
+
+	var ed exportDirectory
+	if err := readAt(f, dir.VirtualAddress, &ed); err != nil {
+		return nil, fmt.Errorf("failed to read export directory: %w", err)
+	}
+
+	// The name table comes from the DLL itself, so check that it fits in its
+	// section before trusting NumberOfNames.
+	table := sectionFor(f, ed.AddressOfNames)
+	if ed.NumberOfNames > 0 && table == nil {
+		return nil, fmt.Errorf("failed to read export name table: rva 0x%x is outside of all sections", ed.AddressOfNames)
+	}
+	if table != nil {
+		available := int64(table.Size) - int64(ed.AddressOfNames-table.VirtualAddress)
+		if int64(ed.NumberOfNames)*4 > available {
+			return nil, fmt.Errorf("failed to read export name table: %d names do not fit in section %s", ed.NumberOfNames, table.Name)
+		}
+	}
+
+	names := make([]string, 0, ed.NumberOfNames)
+	for i := uint32(0); i < ed.NumberOfNames; i++ {
+		var nameRVA uint32
+		if err := readAt(f, ed.AddressOfNames+i*4, &nameRVA); err != nil {
+			return nil, fmt.Errorf("failed to read export name table: %w", err)
+		}
+
+		name, err := readString(f, nameRVA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read export name: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func sectionFor(f *pe.File, rva uint32) *pe.Section {
+	for _, s := range f.Sections {
+		size := s.VirtualSize
+		if size == 0 {
+			size = s.Size
+		}
+		if rva >= s.VirtualAddress && rva < s.VirtualAddress+size {
+			return s
+		}
+	}
+	return nil
+}
+
+func readAt(f *pe.File, rva uint32, data interface{}) error {
+	s := sectionFor(f, rva)
+	if s == nil {
+		return fmt.Errorf("rva 0x%x is outside of all sections", rva)
+	}
+
+	r := io.NewSectionReader(s, int64(rva-s.VirtualAddress), int64(binary.Size(data)))
+	return binary.Read(r, binary.LittleEndian, data)
+}
+
+func readString(f *pe.File, rva uint32) (string, error) {
+	s := sectionFor(f, rva)
+	if s == nil {
+		return "", fmt.Errorf("rva 0x%x is outside of all sections", rva)
+	}
+
+	buf := make([]byte, 256)
+	n, err := s.ReadAt(buf, int64(rva-s.VirtualAddress))
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	buf = buf[:n]
+	if idx := bytes.IndexByte(buf, 0); idx >= 0 {
+		buf = buf[:idx]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,169 @@
+package native
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const exportRVA = 0x1000
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		name    string
+		dll     []byte
+		want    *Info
+		wantErr string
+	}{
+		{
+			name: "well-formed",
+			dll:  buildDLL(exportTable([]string{"luaopen_lfs", "lfs_version"}, 0)),
+			want: &Info{
+				Machine: MachineX86,
+				Exports: []string{"lfs_version", "luaopen_lfs"},
+				LuaOpen: []string{"luaopen_lfs"},
+				Imports: []string{},
+			},
+		},
+		{
+			name: "no exports",
+			dll:  buildDLL(nil),
+			want: &Info{Machine: MachineX86, Exports: []string{}, LuaOpen: []string{}, Imports: []string{}},
+		},
+		{
+			name:    "corrupt name count",
+			dll:     buildDLL(exportTable([]string{"luaopen_lfs"}, 0x7fffffff)),
+			wantErr: "failed to read export name table: 2147483647 names do not fit in section .edata",
+		},
+		{
+			name:    "truncated export directory",
+			dll:     buildDLL(exportTable([]string{"luaopen_lfs"}, 0))[:0x210],
+			wantErr: "failed to read export directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.dll")
+			if err := os.WriteFile(path, tt.dll, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Inspect(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Inspect = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLuaOpenSymbol(t *testing.T) {
+	tests := []struct {
+		module string
+		want   string
+	}{
+		{"lfs", "luaopen_lfs"},
+		{"socket.core", "luaopen_socket_core"},
+		{"v2-mod", "luaopen_mod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			if got := LuaOpenSymbol(tt.module); got != tt.want {
+				t.Errorf("LuaOpenSymbol(%q) = %q, want %q", tt.module, got, tt.want)
+			}
+		})
+	}
+}
+
+// exportTable lays out an export directory, its name pointer table and the
+// names themselves, as they appear in a section mapped at exportRVA. A
+// non-zero count overrides the number of names recorded in the directory.
+func exportTable(names []string, count uint32) []byte {
+	if count == 0 {
+		count = uint32(len(names))
+	}
+
+	dirSize := uint32(binary.Size(exportDirectory{}))
+	tableRVA := exportRVA + dirSize
+	nameRVA := tableRVA + uint32(len(names))*4
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, exportDirectory{
+		NumberOfNames:  count,
+		AddressOfNames: tableRVA,
+	})
+	for _, name := range names {
+		binary.Write(&buf, binary.LittleEndian, nameRVA)
+		nameRVA += uint32(len(name)) + 1
+	}
+	for _, name := range names {
+		buf.WriteString(name)
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+// buildDLL wraps section data in the smallest PE32 image debug/pe accepts,
+// with the export directory pointing at the start of the section.
+func buildDLL(section []byte) []byte {
+	const (
+		peOffset   = 0x40
+		dataOffset = 0x200
+	)
+
+	var buf bytes.Buffer
+	dos := make([]byte, peOffset)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3c:], peOffset)
+	buf.Write(dos)
+	buf.WriteString("PE\x00\x00")
+
+	optional := pe.OptionalHeader32{
+		Magic:               0x10b,
+		SectionAlignment:    0x1000,
+		FileAlignment:       0x200,
+		NumberOfRvaAndSizes: 16,
+	}
+	if len(section) > 0 {
+		optional.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_EXPORT] = pe.DataDirectory{
+			VirtualAddress: exportRVA,
+			Size:           uint32(len(section)),
+		}
+	}
+
+	binary.Write(&buf, binary.LittleEndian, pe.FileHeader{
+		Machine:              pe.IMAGE_FILE_MACHINE_I386,
+		NumberOfSections:     1,
+		SizeOfOptionalHeader: uint16(binary.Size(optional)),
+		Characteristics:      pe.IMAGE_FILE_DLL | pe.IMAGE_FILE_EXECUTABLE_IMAGE,
+	})
+	binary.Write(&buf, binary.LittleEndian, optional)
+
+	header := pe.SectionHeader32{
+		VirtualSize:      uint32(len(section)),
+		VirtualAddress:   exportRVA,
+		SizeOfRawData:    uint32(len(section)),
+		PointerToRawData: dataOffset,
+	}
+	copy(header.Name[:], ".edata")
+	binary.Write(&buf, binary.LittleEndian, header)
+
+	buf.Write(make([]byte, dataOffset-buf.Len()))
+	buf.Write(section)
+	return buf.Bytes()
+}
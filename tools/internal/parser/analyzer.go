@@ -0,0 +1,248 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type SourceFile struct {
+	Path   string
+	Source string
+	Chunk  *Chunk
+	Result *SourceResult
+}
+
+type Analyzer interface {
+	Name() string
+	Analyze(file *SourceFile) []Warning
+}
+
+var (
+	analyzersMu sync.RWMutex
+	analyzers   = make(map[string]Analyzer)
+)
+
+func init() {
+	RegisterAnalyzer(dynamicRequireAnalyzer{})
+	RegisterAnalyzer(obfuscationAnalyzer{})
+}
+
+func RegisterAnalyzer(a Analyzer) {
+	analyzersMu.Lock()
+	defer analyzersMu.Unlock()
+
+	if _, exists := analyzers[a.Name()]; exists {
+		panic("parser: analyzer " + a.Name() + " registered twice")
+	}
+	analyzers[a.Name()] = a
+}
+
+func AnalyzerNames() []string {
+	analyzersMu.RLock()
+	defer analyzersMu.RUnlock()
+
+	names := make([]string, 0, len(analyzers))
+	for name := range analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func DefaultAnalyzers() []Analyzer {
+	selected, _ := SelectAnalyzers(nil, nil)
+	return selected
+}
+
+func SelectAnalyzers(enable, disable []string) ([]Analyzer, error) {
+	analyzersMu.RLock()
+	defer analyzersMu.RUnlock()
+
+	for _, name := range append(append([]string{}, enable...), disable...) {
+		if _, ok := analyzers[name]; !ok {
+			return nil, fmt.Errorf("unknown analyzer %q", name)
+		}
+	}
+
+	names := enable
+	if len(names) == 0 {
+		for name := range analyzers {
+			names = append(names, name)
+		}
+	}
+
+	disabled := make(map[string]bool)
+	for _, name := range disable {
+		disabled[name] = true
+	}
+
+	selected := []Analyzer{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if disabled[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+		selected = append(selected, analyzers[name])
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name() < selected[j].Name()
+	})
+
+	return selected, nil
+}
+
+type FileAnalysis struct {
+	Result   *SourceResult
+	Findings map[string][]Warning
+}
+
+func (c *Context) analyzeFile(path string, content []byte) *FileAnalysis {
+	hash := ""
+	var file *FileAnalysis
+	if c.Cache != nil {
+		hash = contentHash(content)
+		file, _ = c.Cache.Load(hash)
+	}
+
+	dirty := file == nil
+	parsed := false
+	var chunk *Chunk
+	if file == nil {
+		var result *SourceResult
+		result, chunk = parseFile(string(content))
+		parsed = true
+		file = &FileAnalysis{
+			Result:   result,
+			Findings: make(map[string][]Warning),
+		}
+	}
+	if file.Findings == nil {
+		file.Findings = make(map[string][]Warning)
+	}
+
+	var source *SourceFile
+	for _, a := range c.Analyzers {
+		if _, ok := file.Findings[a.Name()]; ok {
+			continue
+		}
+		if source == nil {
+			if !parsed {
+				chunk = parseChunk(string(content))
+			}
+			source = &SourceFile{
+				Path:   path,
+				Source: string(content),
+				Chunk:  chunk,
+				Result: file.Result,
+			}
+		}
+
+		findings := a.Analyze(source)
+		if findings == nil {
+			findings = []Warning{}
+		}
+		file.Findings[a.Name()] = findings
+		dirty = true
+	}
+
+	if dirty && c.Cache != nil {
+		c.Cache.Store(hash, file)
+	}
+
+	return file
+}
+
+func parseChunk(source string) *Chunk {
+	if IsBytecode(source) {
+		return nil
+	}
+	if chunk, err := Parse(source); err == nil {
+		return chunk
+	}
+	return nil
+}
+
+type dynamicRequireAnalyzer struct{}
+
+func (dynamicRequireAnalyzer) Name() string {
+	return "dynamic-requires"
+}
+
+func (dynamicRequireAnalyzer) Analyze(file *SourceFile) []Warning {
+	warnings := []Warning{}
+	for _, w := range file.Result.Warnings {
+		if w.Type.IsDynamicRequire() {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+type obfuscationAnalyzer struct{}
+
+func (obfuscationAnalyzer) Name() string {
+	return "obfuscation"
+}
+
+func (obfuscationAnalyzer) Analyze(file *SourceFile) []Warning {
+	if IsBytecode(file.Source) {
+		warnings := []Warning{}
+		for _, w := range file.Result.Warnings {
+			if w.Type == WarningBytecode {
+				warnings = append(warnings, w)
+			}
+		}
+		return warnings
+	}
+
+	warnings := detectLongLines(file.Source)
+	if file.Chunk != nil {
+		warnings = append(warnings, detectHighEntropyTables(file.Chunk)...)
+	}
+	return warnings
+}
+
+type CallAnalyzer struct {
+	ID       string
+	Calls    map[string]string
+	Severity Severity
+}
+
+func (a *CallAnalyzer) Name() string {
+	return a.ID
+}
+
+func (a *CallAnalyzer) Analyze(file *SourceFile) []Warning {
+	warnings := []Warning{}
+	if file.Chunk == nil {
+		return warnings
+	}
+
+	Inspect(file.Chunk.Block, func(node Node) bool {
+		call, ok := node.(*CallExpr)
+		if !ok {
+			return true
+		}
+
+		name := qualifiedName(call.Func)
+		if message, banned := a.Calls[name]; banned {
+			pos := call.Pos()
+			warnings = append(warnings, Warning{
+				Type:     WarningAnalyzer,
+				Rule:     a.ID,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Module:   name,
+				Severity: a.Severity,
+				Message:  message,
+			})
+		}
+
+		return true
+	})
+
+	return warnings
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeFileHonoursSelection(t *testing.T) {
+	minified := strings.Repeat("a=1 ", 400) + "\nlocal m = require(name)\n"
+	bytecode := "\x1bLJ\x02\x00"
+
+	tests := []struct {
+		name    string
+		source  string
+		disable []string
+		want    map[string][]WarningType
+	}{
+		{
+			name:   "all analyzers",
+			source: minified,
+			want: map[string][]WarningType{
+				"dynamic-requires": {WarningVariableRequire},
+				"obfuscation":      {WarningObfuscatedLine},
+			},
+		},
+		{
+			name:    "obfuscation disabled",
+			source:  minified,
+			disable: []string{"obfuscation"},
+			want: map[string][]WarningType{
+				"dynamic-requires": {WarningVariableRequire},
+			},
+		},
+		{
+			name:   "bytecode",
+			source: bytecode,
+			want: map[string][]WarningType{
+				"dynamic-requires": {},
+				"obfuscation":      {WarningBytecode},
+			},
+		},
+		{
+			name:    "bytecode with obfuscation disabled",
+			source:  bytecode,
+			disable: []string{"obfuscation"},
+			want: map[string][]WarningType{
+				"dynamic-requires": {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzers, err := SelectAnalyzers(nil, tt.disable)
+			if err != nil {
+				t.Fatalf("SelectAnalyzers: %v", err)
+			}
+			ctx := &Context{Analyzers: analyzers, Cache: NewCache(t.TempDir())}
+
+			for _, pass := range []string{"fresh", "cached"} {
+				file := ctx.analyzeFile("main.lua", []byte(tt.source))
+
+				got := make(map[string][]WarningType)
+				for name, findings := range file.Findings {
+					types := []WarningType{}
+					for _, w := range findings {
+						types = append(types, w.Type)
+					}
+					got[name] = types
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s findings = %v, want %v", pass, got, tt.want)
+				}
+			}
+		})
+	}
+}
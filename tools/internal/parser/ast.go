@@ -0,0 +1,316 @@
+package parser
+
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) Pos() Position {
+	return p
+}
+
+type Node interface {
+	Pos() Position
+}
+
+type Expr interface {
+	Node
+	exprNode()
+}
+
+type Stmt interface {
+	Node
+	stmtNode()
+}
+
+type Chunk struct {
+	Block *Block
+}
+
+type Block struct {
+	Position
+	Stmts []Stmt
+}
+
+type NilExpr struct{ Position }
+type TrueExpr struct{ Position }
+type FalseExpr struct{ Position }
+type VarargExpr struct{ Position }
+
+type NumberExpr struct {
+	Position
+	Value string
+}
+
+type StringExpr struct {
+	Position
+	Value string
+	Long  bool
+}
+
+type NameExpr struct {
+	Position
+	Name string
+}
+
+type IndexExpr struct {
+	Position
+	Object Expr
+	Key    Expr
+}
+
+type CallExpr struct {
+	Position
+	Func Expr
+	Args []Expr
+}
+
+type MethodCallExpr struct {
+	Position
+	Object Expr
+	Method string
+	Args   []Expr
+}
+
+type FunctionExpr struct {
+	Position
+	Params   []string
+	IsVararg bool
+	Body     *Block
+}
+
+type TableField struct {
+	Key   Expr
+	Value Expr
+}
+
+type TableExpr struct {
+	Position
+	Fields []*TableField
+}
+
+type BinaryExpr struct {
+	Position
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+type UnaryExpr struct {
+	Position
+	Op      string
+	Operand Expr
+}
+
+type ParenExpr struct {
+	Position
+	Inner Expr
+}
+
+func (*NilExpr) exprNode()        {}
+func (*TrueExpr) exprNode()       {}
+func (*FalseExpr) exprNode()      {}
+func (*VarargExpr) exprNode()     {}
+func (*NumberExpr) exprNode()     {}
+func (*StringExpr) exprNode()     {}
+func (*NameExpr) exprNode()       {}
+func (*IndexExpr) exprNode()      {}
+func (*CallExpr) exprNode()       {}
+func (*MethodCallExpr) exprNode() {}
+func (*FunctionExpr) exprNode()   {}
+func (*TableExpr) exprNode()      {}
+func (*BinaryExpr) exprNode()     {}
+func (*UnaryExpr) exprNode()      {}
+func (*ParenExpr) exprNode()      {}
+
+type LocalStmt struct {
+	Position
+	Names   []string
+	Attribs []string
+	Values  []Expr
+}
+
+type AssignStmt struct {
+	Position
+	Targets []Expr
+	Values  []Expr
+}
+
+type CallStmt struct {
+	Position
+	Call Expr
+}
+
+type DoStmt struct {
+	Position
+	Body *Block
+}
+
+type WhileStmt struct {
+	Position
+	Cond Expr
+	Body *Block
+}
+
+type RepeatStmt struct {
+	Position
+	Body *Block
+	Cond Expr
+}
+
+type IfClause struct {
+	Cond Expr
+	Body *Block
+}
+
+type IfStmt struct {
+	Position
+	Clauses []*IfClause
+	Else    *Block
+}
+
+type NumericForStmt struct {
+	Position
+	Var   string
+	Start Expr
+	Limit Expr
+	Step  Expr
+	Body  *Block
+}
+
+type GenericForStmt struct {
+	Position
+	Names []string
+	Exprs []Expr
+	Body  *Block
+}
+
+type FunctionStmt struct {
+	Position
+	Name     Expr
+	IsMethod bool
+	Func     *FunctionExpr
+}
+
+type LocalFunctionStmt struct {
+	Position
+	Name string
+	Func *FunctionExpr
+}
+
+type ReturnStmt struct {
+	Position
+	Values []Expr
+}
+
+type BreakStmt struct{ Position }
+
+type GotoStmt struct {
+	Position
+	Label string
+}
+
+type LabelStmt struct {
+	Position
+	Name string
+}
+
+func (*LocalStmt) stmtNode()         {}
+func (*AssignStmt) stmtNode()        {}
+func (*CallStmt) stmtNode()          {}
+func (*DoStmt) stmtNode()            {}
+func (*WhileStmt) stmtNode()         {}
+func (*RepeatStmt) stmtNode()        {}
+func (*IfStmt) stmtNode()            {}
+func (*NumericForStmt) stmtNode()    {}
+func (*GenericForStmt) stmtNode()    {}
+func (*FunctionStmt) stmtNode()      {}
+func (*LocalFunctionStmt) stmtNode() {}
+func (*ReturnStmt) stmtNode()        {}
+func (*BreakStmt) stmtNode()         {}
+func (*GotoStmt) stmtNode()          {}
+func (*LabelStmt) stmtNode()         {}
+
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Block:
+		for _, s := range n.Stmts {
+			Inspect(s, f)
+		}
+	case *IndexExpr:
+		Inspect(n.Object, f)
+		Inspect(n.Key, f)
+	case *CallExpr:
+		Inspect(n.Func, f)
+		inspectExprs(n.Args, f)
+	case *MethodCallExpr:
+		Inspect(n.Object, f)
+		inspectExprs(n.Args, f)
+	case *FunctionExpr:
+		Inspect(n.Body, f)
+	case *TableExpr:
+		for _, field := range n.Fields {
+			if field.Key != nil {
+				Inspect(field.Key, f)
+			}
+			Inspect(field.Value, f)
+		}
+	case *BinaryExpr:
+		Inspect(n.Left, f)
+		Inspect(n.Right, f)
+	case *UnaryExpr:
+		Inspect(n.Operand, f)
+	case *ParenExpr:
+		Inspect(n.Inner, f)
+	case *LocalStmt:
+		inspectExprs(n.Values, f)
+	case *AssignStmt:
+		inspectExprs(n.Targets, f)
+		inspectExprs(n.Values, f)
+	case *CallStmt:
+		Inspect(n.Call, f)
+	case *DoStmt:
+		Inspect(n.Body, f)
+	case *WhileStmt:
+		Inspect(n.Cond, f)
+		Inspect(n.Body, f)
+	case *RepeatStmt:
+		Inspect(n.Body, f)
+		Inspect(n.Cond, f)
+	case *IfStmt:
+		for _, clause := range n.Clauses {
+			Inspect(clause.Cond, f)
+			Inspect(clause.Body, f)
+		}
+		if n.Else != nil {
+			Inspect(n.Else, f)
+		}
+	case *NumericForStmt:
+		Inspect(n.Start, f)
+		Inspect(n.Limit, f)
+		if n.Step != nil {
+			Inspect(n.Step, f)
+		}
+		Inspect(n.Body, f)
+	case *GenericForStmt:
+		inspectExprs(n.Exprs, f)
+		Inspect(n.Body, f)
+	case *FunctionStmt:
+		Inspect(n.Name, f)
+		Inspect(n.Func, f)
+	case *LocalFunctionStmt:
+		Inspect(n.Func, f)
+	case *ReturnStmt:
+		inspectExprs(n.Values, f)
+	}
+}
+
+func inspectExprs(exprs []Expr, f func(Node) bool) {
+	for _, e := range exprs {
+		Inspect(e, f)
+	}
+}
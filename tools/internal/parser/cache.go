@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const AnalyzerVersion = "7"
+
+var DefaultCacheDir = filepath.Join("..", "tools", ".cache", "analysis")
+
+type Cache struct {
+	Dir string
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{Dir: dir}
+}
+
+func (c *Cache) path(hash string) string {
+	return filepath.Join(c.Dir, AnalyzerVersion, hash[:2], hash+".json")
+}
+
+func (c *Cache) Load(hash string) (*FileAnalysis, bool) {
+	data, err := os.ReadFile(c.path(hash))
+	if err != nil {
+		return nil, false
+	}
+
+	var file FileAnalysis
+	if err := json.Unmarshal(data, &file); err != nil || file.Result == nil {
+		return nil, false
+	}
+
+	return &file, true
+}
+
+func (c *Cache) Store(hash string, file *FileAnalysis) error {
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.Dir)
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	content := []byte("local vkeys = require('vkeys')")
+	hash := contentHash(content)
+	stored := &FileAnalysis{
+		Result:   &SourceResult{RawModules: []string{"cached"}},
+		Findings: map[string][]Warning{},
+	}
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, c *Cache)
+		want  []string
+	}{
+		{
+			name:  "miss",
+			setup: func(t *testing.T, c *Cache) {},
+			want:  []string{"vkeys"},
+		},
+		{
+			name: "hit",
+			setup: func(t *testing.T, c *Cache) {
+				if err := c.Store(hash, stored); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"cached"},
+		},
+		{
+			name: "older analyzer version",
+			setup: func(t *testing.T, c *Cache) {
+				if err := c.Store(hash, stored); err != nil {
+					t.Fatal(err)
+				}
+				old := filepath.Join(c.Dir, "old")
+				if err := os.Rename(filepath.Join(c.Dir, AnalyzerVersion), old); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"vkeys"},
+		},
+		{
+			name: "corrupt entry",
+			setup: func(t *testing.T, c *Cache) {
+				path := c.path(hash)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"vkeys"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCache(t.TempDir())
+			tt.setup(t, cache)
+
+			ctx := &Context{Cache: cache}
+			file := ctx.analyzeFile("main.lua", content)
+			if !reflect.DeepEqual(file.Result.RawModules, tt.want) {
+				t.Errorf("modules = %v, want %v", file.Result.RawModules, tt.want)
+			}
+
+			loaded, ok := cache.Load(hash)
+			if !ok {
+				t.Fatalf("Load after analyzeFile: miss, want hit")
+			}
+			if !reflect.DeepEqual(loaded.Result.RawModules, tt.want) {
+				t.Errorf("cached modules = %v, want %v", loaded.Result.RawModules, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+type CapabilityKind string
+
+const (
+	CapabilityNetwork     CapabilityKind = "network"
+	CapabilityFFI         CapabilityKind = "ffi"
+	CapabilityFileAccess  CapabilityKind = "fileAccess"
+	CapabilityProcess     CapabilityKind = "process"
+	CapabilityDynamicCode CapabilityKind = "dynamicCode"
+	CapabilityDownload    CapabilityKind = "download"
+	CapabilityMemoryWrite CapabilityKind = "memoryWrite"
+	CapabilityThreads     CapabilityKind = "threads"
+)
+
+type Capability struct {
+	Kind    CapabilityKind
+	Detail  string
+	File    string
+	Line    int
+	Column  int
+	Dynamic bool
+}
+
+var capabilityCalls = map[string]CapabilityKind{
+	"os.execute":        CapabilityProcess,
+	"io.popen":          CapabilityProcess,
+	"downloadUrlToFile": CapabilityDownload,
+	"effil.thread":      CapabilityThreads,
+	"lanes.gen":         CapabilityThreads,
+}
+
+var capabilityModules = map[string]CapabilityKind{
+	"effil":    CapabilityThreads,
+	"lanes":    CapabilityThreads,
+	"requests": CapabilityNetwork,
+	"lcurl":    CapabilityNetwork,
+}
+
+var memoryWriteCalls = []string{
+	"copy",
+	"fill",
+	"hex2bin",
+	"protect",
+	"unprotect",
+	"write",
+}
+
+func (r *SourceResult) inspectCapabilityCall(name string, call *CallExpr, s *scope) {
+	if kind, ok := capabilityCalls[name]; ok {
+		r.addCapability(kind, name, call.Pos())
+		return
+	}
+
+	switch name {
+	case "load", "loadstring":
+		if len(call.Args) > 0 && s.evalString(call.Args[0]) == nil {
+			r.addCapability(CapabilityDynamicCode, name, call.Pos())
+		}
+		return
+	}
+
+	if fn, ok := strings.CutPrefix(name, "memory."); ok && isMemoryWrite(fn) {
+		r.addCapability(CapabilityMemoryWrite, name, call.Pos())
+	}
+}
+
+func (r *SourceResult) inspectCapabilityModule(module string, pos Position) {
+	root := strings.SplitN(rootModuleName(module), ".", 2)[0]
+	if kind, ok := capabilityModules[root]; ok {
+		r.addCapability(kind, module, pos)
+	}
+}
+
+func isMemoryWrite(fn string) bool {
+	if strings.HasPrefix(fn, "set") {
+		return true
+	}
+	for _, call := range memoryWriteCalls {
+		if fn == call {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *SourceResult) addCapability(kind CapabilityKind, detail string, pos Position) {
+	r.Capabilities = append(r.Capabilities, Capability{
+		Kind:   kind,
+		Detail: detail,
+		Line:   pos.Line,
+		Column: pos.Column,
+	})
+	if kind == CapabilityNetwork || kind == CapabilityDownload {
+		r.UsesNetwork = true
+	}
+}
+
+func SummarizeCapabilities(capabilities []Capability) []Capability {
+	seen := make(map[string]bool)
+	summary := []Capability{}
+
+	sorted := append([]Capability{}, capabilities...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Detail != b.Detail {
+			return a.Detail < b.Detail
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	for _, capability := range sorted {
+		key := string(capability.Kind) + "\x00" + capability.Detail + "\x00" + capability.File
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		summary = append(summary, capability)
+	}
+
+	return summary
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNetworkCapabilities(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "global module",
+			source: "socket.tcp()",
+			want:   []string{"socket.tcp"},
+		},
+		{
+			name:   "inline require",
+			source: "require('socket').connect('host', 80)",
+			want:   []string{"socket.connect"},
+		},
+		{
+			name:   "module alias",
+			source: "local s = require('socket')\ns.tcp()",
+			want:   []string{"socket.tcp"},
+		},
+		{
+			name:   "submodule alias",
+			source: "local h = require 'socket.http'\nh.request('http://example.com')",
+			want:   []string{"socket.http.request"},
+		},
+		{
+			name:   "unrelated local",
+			source: "local s = {}\ns.tcp()",
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSource(tt.source)
+			if err != nil {
+				t.Fatalf("ParseSource: %v", err)
+			}
+
+			got := []string{}
+			for _, c := range result.Capabilities {
+				if c.Kind == CapabilityNetwork {
+					got = append(got, c.Detail)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("network capabilities = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"strings"
+)
+
+type CompatLevel string
+
+const (
+	CompatLuaJIT CompatLevel = "luajit"
+	CompatLua53  CompatLevel = "lua5.3"
+	CompatLua54  CompatLevel = "lua5.4"
+)
+
+type CompatFeature struct {
+	Feature string
+	Level   CompatLevel
+	Syntax  bool
+	Guarded bool
+	File    string
+	Line    int
+	Column  int
+}
+
+var compatOperators = map[string]bool{
+	"//": true,
+	"&":  true,
+	"|":  true,
+	"~":  true,
+	"<<": true,
+	">>": true,
+}
+
+var compatLibrary = map[string]CompatLevel{
+	"coroutine.isyieldable": CompatLua53,
+	"math.tointeger":        CompatLua53,
+	"math.type":             CompatLua53,
+	"math.ult":              CompatLua53,
+	"string.pack":           CompatLua53,
+	"string.packsize":       CompatLua53,
+	"string.unpack":         CompatLua53,
+	"table.move":            CompatLua53,
+	"utf8":                  CompatLua53,
+	"warn":                  CompatLua54,
+	"coroutine.close":       CompatLua54,
+}
+
+func compatRank(level CompatLevel) int {
+	switch level {
+	case CompatLua53:
+		return 1
+	case CompatLua54:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func MaxCompatLevel(features []CompatFeature) CompatLevel {
+	level := CompatLuaJIT
+	for _, f := range features {
+		if !f.Guarded && compatRank(f.Level) > compatRank(level) {
+			level = f.Level
+		}
+	}
+	return level
+}
+
+func (r *SourceResult) addCompat(feature string, level CompatLevel, syntax, guarded bool, pos Position) {
+	r.Compat = append(r.Compat, CompatFeature{
+		Feature: feature,
+		Level:   level,
+		Syntax:  syntax,
+		Guarded: guarded,
+		Line:    pos.Line,
+		Column:  pos.Column,
+	})
+}
+
+func (e *extractor) inspectCompatLibrary(expr Expr, called bool) {
+	name := qualifiedName(expr)
+	if name == "" {
+		return
+	}
+
+	head, _, _ := strings.Cut(name, ".")
+	if e.scope.declared(head) {
+		return
+	}
+
+	level, ok := compatLibrary[name]
+	if !ok && head == "utf8" && name != head {
+		level, ok = compatLibrary[head]
+	}
+	if !ok {
+		return
+	}
+
+	e.result.addCompat(name, level, false, e.probe > 0 || !called, expr.Pos())
+}
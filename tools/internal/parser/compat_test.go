@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCompatFeatures(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+		level  CompatLevel
+	}{
+		{
+			name:   "plain luajit",
+			source: "local t = {}\ntable.insert(t, math.floor(1.5))",
+			want:   []string{},
+			level:  CompatLuaJIT,
+		},
+		{
+			name:   "goto and label",
+			source: "goto done\n::done::",
+			want:   []string{"goto luajit", "::label:: luajit"},
+			level:  CompatLuaJIT,
+		},
+		{
+			name:   "integer division",
+			source: "local x = 7 // 2",
+			want:   []string{"// lua5.3"},
+			level:  CompatLua53,
+		},
+		{
+			name:   "bitwise operators",
+			source: "local x = ~(1 << 4) | 2",
+			want:   []string{"| lua5.3", "~ lua5.3", "<< lua5.3"},
+			level:  CompatLua53,
+		},
+		{
+			name:   "library call",
+			source: "local s = string.pack('i4', 1)",
+			want:   []string{"string.pack lua5.3"},
+			level:  CompatLua53,
+		},
+		{
+			name:   "utf8 member",
+			source: "local c = utf8.char(72)",
+			want:   []string{"utf8.char lua5.3"},
+			level:  CompatLua53,
+		},
+		{
+			name:   "lua 5.4 attribute",
+			source: "local x <const> = 1",
+			want:   []string{"<const> lua5.4"},
+			level:  CompatLua54,
+		},
+		{
+			name:   "lua 5.4 wins over 5.3",
+			source: "local x = 1 // 2\nwarn('x')",
+			want:   []string{"// lua5.3", "warn lua5.4"},
+			level:  CompatLua54,
+		},
+		{
+			name:   "guarded by if",
+			source: "if table.move then table.move({}, 1, 1, 1) end",
+			want:   []string{"table.move lua5.3 guarded", "table.move lua5.3"},
+			level:  CompatLua53,
+		},
+		{
+			name:   "guarded by or",
+			source: "local move = table.move or function() end",
+			want:   []string{"table.move lua5.3 guarded"},
+			level:  CompatLuaJIT,
+		},
+		{
+			name:   "shadowed by local",
+			source: "local utf8 = {}\nutf8.char(72)",
+			want:   []string{},
+			level:  CompatLuaJIT,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSource(tt.source)
+			if err != nil {
+				t.Fatalf("ParseSource: %v", err)
+			}
+
+			got := []string{}
+			for _, f := range result.Compat {
+				s := fmt.Sprintf("%s %s", f.Feature, f.Level)
+				if f.Guarded {
+					s += " guarded"
+				}
+				got = append(got, s)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compat = %v, want %v", got, tt.want)
+			}
+			if level := MaxCompatLevel(result.Compat); level != tt.level {
+				t.Errorf("MaxCompatLevel = %s, want %s", level, tt.level)
+			}
+		})
+	}
+}
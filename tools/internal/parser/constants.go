@@ -0,0 +1,173 @@
+package parser
+
+import "strings"
+
+const maxConstantValues = 64
+
+type binding struct {
+	values   []string
+	list     []string
+	template string
+	module   string
+}
+
+type scope struct {
+	parent *scope
+	vars   map[string]*binding
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{
+		parent: parent,
+		vars:   make(map[string]*binding),
+	}
+}
+
+func (s *scope) declare(name string, b *binding) {
+	s.vars[name] = b
+}
+
+func (s *scope) lookup(name string) *binding {
+	for cur := s; cur != nil; cur = cur.parent {
+		if b, ok := cur.vars[name]; ok {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *scope) declared(name string) bool {
+	for cur := s; cur != nil; cur = cur.parent {
+		if _, ok := cur.vars[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *scope) assign(name string) {
+	for cur := s; cur != nil; cur = cur.parent {
+		if _, ok := cur.vars[name]; ok {
+			cur.vars[name] = nil
+			return
+		}
+	}
+}
+
+func (s *scope) evalString(expr Expr) []string {
+	switch e := expr.(type) {
+	case *StringExpr:
+		return []string{e.Value}
+	case *NumberExpr:
+		return []string{e.Value}
+	case *ParenExpr:
+		return s.evalString(e.Inner)
+	case *NameExpr:
+		if b := s.lookup(e.Name); b != nil {
+			return b.values
+		}
+	case *BinaryExpr:
+		if e.Op != ".." {
+			return nil
+		}
+		left := s.evalString(e.Left)
+		right := s.evalString(e.Right)
+		if left == nil || right == nil || len(left)*len(right) > maxConstantValues {
+			return nil
+		}
+
+		values := make([]string, 0, len(left)*len(right))
+		for _, l := range left {
+			for _, r := range right {
+				values = append(values, l+r)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+func (s *scope) evalList(expr Expr) []string {
+	switch e := expr.(type) {
+	case *ParenExpr:
+		return s.evalList(e.Inner)
+	case *NameExpr:
+		if b := s.lookup(e.Name); b != nil {
+			return b.list
+		}
+	case *TableExpr:
+		if len(e.Fields) == 0 || len(e.Fields) > maxConstantValues {
+			return nil
+		}
+
+		list := make([]string, 0, len(e.Fields))
+		for _, field := range e.Fields {
+			if field.Key != nil {
+				return nil
+			}
+			values := s.evalString(field.Value)
+			if len(values) != 1 {
+				return nil
+			}
+			list = append(list, values[0])
+		}
+		return list
+	}
+
+	return nil
+}
+
+func (s *scope) evalBinding(expr Expr) *binding {
+	b := &binding{
+		values: s.evalString(expr),
+		list:   s.evalList(expr),
+	}
+	if template := s.evalTemplate(expr); template != unknownPart {
+		b.template = template
+	}
+	if call, ok := expr.(*CallExpr); ok && qualifiedName(call.Func) == "require" && len(call.Args) > 0 {
+		if values := s.evalString(call.Args[0]); len(values) == 1 {
+			b.module = values[0]
+		}
+	}
+	if b.values == nil && b.list == nil && b.template == "" && b.module == "" {
+		return nil
+	}
+	return b
+}
+
+func (s *scope) evalIterator(exprs []Expr) []string {
+	if len(exprs) != 1 {
+		return nil
+	}
+
+	call, ok := exprs[0].(*CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil
+	}
+
+	switch qualifiedName(call.Func) {
+	case "ipairs", "pairs":
+		return s.evalList(call.Args[0])
+	}
+
+	return nil
+}
+
+func (s *scope) qualify(expr Expr) string {
+	name := qualifiedName(expr)
+	if name == "" {
+		return ""
+	}
+
+	head, rest, _ := strings.Cut(name, ".")
+	if b := s.lookup(head); b != nil && b.module != "" {
+		if rest == "" {
+			return b.module
+		}
+		return b.module + "." + rest
+	}
+
+	return name
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConstantRequires(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "local string",
+			source: "local name = 'vkeys'\nrequire(name)",
+			want:   []string{"vkeys"},
+		},
+		{
+			name:   "concatenation",
+			source: "local prefix = 'samp'\nrequire(prefix .. '.events')",
+			want:   []string{"samp.events"},
+		},
+		{
+			name:   "ipairs over a literal table",
+			source: "for _, name in ipairs({'vkeys', 'inicfg'}) do require(name) end",
+			want:   []string{"vkeys", "inicfg"},
+		},
+		{
+			name:   "ipairs over a local table",
+			source: "local mods = {'lfs', 'cjson'}\nfor i, mod in ipairs(mods) do require('lib.' .. mod) end",
+			want:   []string{"lib.lfs", "lib.cjson"},
+		},
+		{
+			name:   "reassigned local",
+			source: "local name = 'vkeys'\nname = get()\nrequire(name)",
+			want:   []string{},
+		},
+		{
+			name:   "shadowed in a nested scope",
+			source: "local name = 'vkeys'\ndo local name = 'inicfg'\nrequire(name) end\nrequire(name)",
+			want:   []string{"inicfg", "vkeys"},
+		},
+		{
+			name:   "table with keys",
+			source: "for _, name in ipairs({a = 'vkeys'}) do require(name) end",
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSource(tt.source)
+			if err != nil {
+				t.Fatalf("ParseSource: %v", err)
+			}
+			if !reflect.DeepEqual(result.RawModules, tt.want) {
+				t.Errorf("modules = %v, want %v", result.RawModules, tt.want)
+			}
+		})
+	}
+}
@@ -9,15 +9,25 @@ import (
 )
 
 type Context struct {
-	PackageID       string
-	PackagePath     string
-	InternalModules map[string]bool
-	Registry        *Registry
+	PackageID         string
+	PackagePath       string
+	InternalModules   map[string]bool
+	ModuleFiles       map[string]string
+	NativeFiles       map[string]bool
+	SearchPaths       []string
+	NativeSearchPaths []string
+	Registry          *Registry
+	Cache             *Cache
+	Analyzers         []Analyzer
+	Trace             *TraceOptions
 }
 
 type Registry struct {
-	packages map[string]*PackageInfo
-	provides map[string]string
+	packages  map[string]*PackageInfo
+	provides  map[string]string
+	natives   map[string]string
+	libraries map[string][]string
+	scripts   map[string]string
 }
 
 type PackageInfo struct {
@@ -25,12 +35,16 @@ type PackageInfo struct {
 	Version  string
 	Provides []string
 	Files    []string
+	Script   bool
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		packages: make(map[string]*PackageInfo),
-		provides: make(map[string]string),
+		packages:  make(map[string]*PackageInfo),
+		provides:  make(map[string]string),
+		natives:   make(map[string]string),
+		libraries: make(map[string][]string),
+		scripts:   make(map[string]string),
 	}
 }
 
@@ -39,6 +53,21 @@ func (r *Registry) AddPackage(info *PackageInfo) {
 	for _, alias := range info.Provides {
 		r.provides[alias] = info.ID
 	}
+	for _, file := range info.Files {
+		if !isNativeFile(file) {
+			continue
+		}
+		name := LibraryName(file)
+		r.libraries[name] = append(r.libraries[name], info.ID)
+		if module := CanonicalModule(file); module != "" {
+			if _, exists := r.natives[module]; !exists {
+				r.natives[module] = info.ID
+			}
+		}
+	}
+	if info.Script {
+		r.addScriptFiles(info)
+	}
 }
 
 func (r *Registry) ResolveModule(modulePath string) string {
@@ -54,6 +83,10 @@ func (r *Registry) ResolveModule(modulePath string) string {
 		if pkgID, ok := r.provides[testPath]; ok {
 			return pkgID
 		}
+
+		if pkgID, ok := r.natives[testPath]; ok {
+			return pkgID
+		}
 	}
 
 	return ""
@@ -65,13 +98,20 @@ func (r *Registry) GetPackage(id string) *PackageInfo {
 
 func NewContext(packageID, packagePath string, registry *Registry) (*Context, error) {
 	internalModules := make(map[string]bool)
+	moduleFiles := make(map[string]string)
+	nativeFiles := make(map[string]bool)
 
 	err := filepath.Walk(packagePath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if info.IsDir() || filepath.Ext(path) != ".lua" {
+		if info.IsDir() {
+			return nil
+		}
+
+		native := isNativeFile(path)
+		if !native && !isLuaSource(path) {
 			return nil
 		}
 
@@ -79,9 +119,24 @@ func NewContext(packageID, packagePath string, registry *Registry) (*Context, er
 		if err != nil {
 			return err
 		}
+		relPath = filepath.ToSlash(relPath)
 
-		modulePath := strings.TrimSuffix(relPath, ".lua")
-		modulePath = strings.ReplaceAll(modulePath, string(filepath.Separator), ".")
+		modulePath := CanonicalModule(relPath)
+		if native {
+			nativeFiles[LibraryName(path)] = true
+			addModuleFile(moduleFiles, packageID, modulePath, relPath)
+			return nil
+		}
+
+		addModuleFile(moduleFiles, packageID, modulePath, relPath)
+		if filepath.Ext(path) != ".lua" {
+			return nil
+		}
+
+		if modulePath == "" {
+			internalModules[packageID] = true
+			return nil
+		}
 
 		internalModules[packageID+"."+modulePath] = true
 		internalModules[modulePath] = true
@@ -94,10 +149,15 @@ func NewContext(packageID, packagePath string, registry *Registry) (*Context, er
 	}
 
 	return &Context{
-		PackageID:       packageID,
-		PackagePath:     packagePath,
-		InternalModules: internalModules,
-		Registry:        registry,
+		PackageID:         packageID,
+		PackagePath:       packagePath,
+		InternalModules:   internalModules,
+		ModuleFiles:       moduleFiles,
+		NativeFiles:       nativeFiles,
+		SearchPaths:       append([]string{}, DefaultSearchPaths...),
+		NativeSearchPaths: append([]string{}, DefaultNativeSearchPaths...),
+		Registry:          registry,
+		Analyzers:         DefaultAnalyzers(),
 	}, nil
 }
 
@@ -118,6 +178,11 @@ func (c *Context) IsInternalModule(modulePath string) bool {
 	return false
 }
 
+func isNativeFile(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".dll" || ext == ".so"
+}
+
 func LoadRegistryFromManifests(basePaths []string) (*Registry, error) {
 	registry := NewRegistry()
 
@@ -162,6 +227,7 @@ func LoadRegistryFromManifests(basePaths []string) (*Registry, error) {
 					Version:  m.Version,
 					Provides: m.Provides,
 					Files:    files,
+					Script:   filepath.Base(basePath) == "scripts",
 				})
 
 				break
@@ -12,6 +12,17 @@ const (
 	WarningVariableRequire
 	WarningTableRequire
 	WarningConcatRequire
+	WarningParseError
+	WarningDynamicNativeLibrary
+	WarningUnresolvedNativeLibrary
+	WarningBytecode
+	WarningObfuscatedLine
+	WarningHighEntropyStrings
+	WarningDynamicImport
+	WarningUnresolvedImport
+	WarningUnresolvedModule
+	WarningAnalyzer
+	WarningTraceIncomplete
 )
 
 type Severity int
@@ -22,14 +33,74 @@ const (
 	SeverityError
 )
 
+var ruleIDs = map[WarningType]string{
+	WarningDynamicRequire:          "dynamic-require",
+	WarningVariableRequire:         "variable-require",
+	WarningTableRequire:            "table-require",
+	WarningConcatRequire:           "concat-require",
+	WarningParseError:              "parse-error",
+	WarningDynamicNativeLibrary:    "dynamic-native-library",
+	WarningUnresolvedNativeLibrary: "unresolved-native-library",
+	WarningBytecode:                "bytecode",
+	WarningObfuscatedLine:          "obfuscated-line",
+	WarningHighEntropyStrings:      "high-entropy-strings",
+	WarningDynamicImport:           "dynamic-import",
+	WarningUnresolvedImport:        "unresolved-import",
+	WarningUnresolvedModule:        "unresolved-module",
+	WarningAnalyzer:                "analyzer",
+	WarningTraceIncomplete:         "trace-incomplete",
+}
+
+func (t WarningType) RuleID() string {
+	return ruleIDs[t]
+}
+
+func (t WarningType) IsDynamicRequire() bool {
+	switch t {
+	case WarningDynamicRequire, WarningVariableRequire, WarningTableRequire, WarningConcatRequire:
+		return true
+	}
+	return false
+}
+
+func (t WarningType) IsDynamic() bool {
+	switch t {
+	case WarningDynamicRequire, WarningVariableRequire, WarningTableRequire, WarningConcatRequire,
+		WarningDynamicNativeLibrary, WarningDynamicImport:
+		return true
+	}
+	return false
+}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
 type Warning struct {
 	Type     WarningType
+	Rule     string
+	File     string
 	Line     int
+	Column   int
 	Module   string
 	Severity Severity
 	Message  string
 }
 
+func (w Warning) RuleID() string {
+	if w.Rule != "" {
+		return w.Rule
+	}
+	return w.Type.RuleID()
+}
+
 var dynamicPatterns = []struct {
 	pattern  *regexp.Regexp
 	warnType WarningType
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var decimalNumber = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+type Directives struct {
+	Name          string
+	Version       string
+	VersionNumber int
+	Authors       []string
+	Description   string
+	URL           string
+	MoonLoader    int
+	Properties    []string
+	Dependencies  []string
+}
+
+func ParseDirectives(source string) (*Directives, error) {
+	chunk, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExtractDirectives(chunk), nil
+}
+
+func ExtractDirectives(chunk *Chunk) *Directives {
+	d := &Directives{
+		Authors:      []string{},
+		Properties:   []string{},
+		Dependencies: []string{},
+	}
+	s := newScope(nil)
+
+	for _, stmt := range chunk.Block.Stmts {
+		switch n := stmt.(type) {
+		case *LocalStmt:
+			for i, name := range n.Names {
+				var b *binding
+				if i < len(n.Values) {
+					b = s.evalBinding(n.Values[i])
+				}
+				s.declare(name, b)
+			}
+		case *CallStmt:
+			call, ok := n.Call.(*CallExpr)
+			if !ok {
+				continue
+			}
+			d.apply(qualifiedName(call.Func), directiveArgs(call.Args, s))
+		}
+	}
+
+	return d
+}
+
+func (d *Directives) apply(name string, args []string) {
+	if len(args) == 0 {
+		return
+	}
+
+	switch name {
+	case "script_name":
+		d.Name = args[0]
+	case "script_version":
+		d.Version = args[0]
+	case "script_version_number":
+		d.VersionNumber = directiveNumber(args[0])
+	case "script_author", "script_authors":
+		d.Authors = append(d.Authors, args...)
+	case "script_description":
+		d.Description = args[0]
+	case "script_url":
+		d.URL = args[0]
+	case "script_moonloader":
+		d.MoonLoader = directiveNumber(args[0])
+	case "script_properties":
+		d.Properties = append(d.Properties, args...)
+	case "script_dependencies":
+		d.Dependencies = append(d.Dependencies, args...)
+	}
+}
+
+func directiveArgs(args []Expr, s *scope) []string {
+	values := []string{}
+	for _, arg := range args {
+		if v := s.evalString(arg); len(v) == 1 {
+			values = append(values, v[0])
+		}
+	}
+	return values
+}
+
+// directiveNumber reads a directive argument as MoonLoader does, in base 10,
+// so a leading zero is not an octal prefix.
+func directiveNumber(value string) int {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return int(n)
+	}
+	if !decimalNumber.MatchString(value) {
+		return 0
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return int(f)
+	}
+	return 0
+}
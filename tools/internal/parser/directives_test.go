@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDirectiveNumbers(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   int
+	}{
+		{"integer", "script_moonloader(26)", 26},
+		{"leading zero", "script_moonloader(026)", 26},
+		{"float", "script_moonloader(26.9)", 26},
+		{"exponent", "script_moonloader(2.6e1)", 26},
+		{"quoted", `script_moonloader("026")`, 26},
+		{"quoted with spaces", `script_moonloader(" 26 ")`, 26},
+		{"quoted hex", `script_moonloader("0x1A")`, 0},
+		{"quoted word", `script_moonloader("latest")`, 0},
+		{"missing value", "script_moonloader()", 0},
+		{"local constant", "local ML = 26\nscript_moonloader(ML)", 26},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseDirectives(tt.source)
+			if err != nil {
+				t.Fatalf("ParseDirectives: %v", err)
+			}
+			if d.MoonLoader != tt.want {
+				t.Errorf("MoonLoader = %d, want %d", d.MoonLoader, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDirectives(t *testing.T) {
+	source := `
+script_name("HUD")
+script_version("1.2")
+script_version_number(12)
+script_authors("alice", "bob")
+script_author("carol")
+script_description("Shows a HUD")
+script_url("https://example.com/hud")
+script_moonloader(26)
+script_properties("work-in-pause")
+script_dependencies("SAMPFUNCS", "mimgui")
+`
+	want := &Directives{
+		Name:          "HUD",
+		Version:       "1.2",
+		VersionNumber: 12,
+		Authors:       []string{"alice", "bob", "carol"},
+		Description:   "Shows a HUD",
+		URL:           "https://example.com/hud",
+		MoonLoader:    26,
+		Properties:    []string{"work-in-pause"},
+		Dependencies:  []string{"SAMPFUNCS", "mimgui"},
+	}
+
+	got, err := ParseDirectives(source)
+	if err != nil {
+		t.Fatalf("ParseDirectives: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDirectives = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,459 @@
+package parser
+
+import (
+	"strings"
+)
+
+type Require struct {
+	Module   string
+	Inferred bool
+	Optional bool
+	Dynamic  bool
+	File     string
+	Line     int
+	Column   int
+}
+
+type SourceResult struct {
+	RawModules        []string
+	OptionalModules   []string
+	SearchPaths       []string
+	NativeSearchPaths []string
+	Requires          []Require
+	Imports           []Require
+	NativeLibraries   []NativeLibrary
+	Capabilities      []Capability
+	Compat            []CompatFeature
+	UsesNetwork       bool
+	UsesFFI           bool
+	FilePaths         []string
+	Warnings          []Warning
+}
+
+var networkCalls = []string{
+	"http.request",
+	"socket.tcp",
+	"socket.connect",
+}
+
+func ParseSource(source string) (*SourceResult, error) {
+	chunk, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExtractFromAST(chunk), nil
+}
+
+type extractor struct {
+	result  *SourceResult
+	scope   *scope
+	guarded int
+	probe   int
+}
+
+func ExtractFromAST(chunk *Chunk) *SourceResult {
+	e := &extractor{
+		result: &SourceResult{
+			RawModules:        []string{},
+			OptionalModules:   []string{},
+			SearchPaths:       []string{},
+			NativeSearchPaths: []string{},
+			Requires:          []Require{},
+			Imports:           []Require{},
+			NativeLibraries:   []NativeLibrary{},
+			Capabilities:      []Capability{},
+			Compat:            []CompatFeature{},
+			FilePaths:         []string{},
+			Warnings:          []Warning{},
+		},
+		scope: newScope(nil),
+	}
+
+	e.block(chunk.Block)
+
+	return e.result
+}
+
+func (e *extractor) push() {
+	e.scope = newScope(e.scope)
+}
+
+func (e *extractor) pop() {
+	e.scope = e.scope.parent
+}
+
+func (e *extractor) block(block *Block) {
+	e.push()
+	e.stmts(block.Stmts)
+	e.pop()
+}
+
+func (e *extractor) stmts(stmts []Stmt) {
+	for _, stmt := range stmts {
+		e.stmt(stmt)
+	}
+}
+
+func (e *extractor) stmt(stmt Stmt) {
+	switch n := stmt.(type) {
+	case *LocalStmt:
+		e.exprs(n.Values)
+		for _, attrib := range n.Attribs {
+			if attrib != "" {
+				e.result.addCompat("<"+attrib+">", CompatLua54, true, false, n.Pos())
+			}
+		}
+		bindings := make([]*binding, len(n.Names))
+		for i := range n.Names {
+			if i < len(n.Values) {
+				bindings[i] = e.scope.evalBinding(n.Values[i])
+			}
+		}
+		for i, name := range n.Names {
+			e.scope.declare(name, bindings[i])
+		}
+	case *LocalFunctionStmt:
+		e.scope.declare(n.Name, nil)
+		e.function(n.Func)
+	case *AssignStmt:
+		e.exprs(n.Targets)
+		e.exprs(n.Values)
+		for i, target := range n.Targets {
+			if name, ok := target.(*NameExpr); ok {
+				e.scope.assign(name.Name)
+			}
+			if i >= len(n.Values) {
+				continue
+			}
+			switch qualifiedName(target) {
+			case "package.path":
+				e.result.SearchPaths = append(e.result.SearchPaths, e.scope.evalSearchPaths(n.Values[i])...)
+			case "package.cpath":
+				e.result.NativeSearchPaths = append(e.result.NativeSearchPaths, e.scope.evalSearchPaths(n.Values[i])...)
+			}
+		}
+	case *CallStmt:
+		e.expr(n.Call)
+	case *DoStmt:
+		e.block(n.Body)
+	case *WhileStmt:
+		e.expr(n.Cond)
+		e.block(n.Body)
+	case *RepeatStmt:
+		e.push()
+		e.stmts(n.Body.Stmts)
+		e.expr(n.Cond)
+		e.pop()
+	case *IfStmt:
+		for _, clause := range n.Clauses {
+			e.probe++
+			e.expr(clause.Cond)
+			e.probe--
+			e.block(clause.Body)
+		}
+		if n.Else != nil {
+			e.block(n.Else)
+		}
+	case *NumericForStmt:
+		e.expr(n.Start)
+		e.expr(n.Limit)
+		if n.Step != nil {
+			e.expr(n.Step)
+		}
+		e.push()
+		e.scope.declare(n.Var, nil)
+		e.block(n.Body)
+		e.pop()
+	case *GenericForStmt:
+		e.exprs(n.Exprs)
+		values := e.scope.evalIterator(n.Exprs)
+		e.push()
+		for i, name := range n.Names {
+			if i == 1 && values != nil {
+				e.scope.declare(name, &binding{values: values})
+			} else {
+				e.scope.declare(name, nil)
+			}
+		}
+		e.block(n.Body)
+		e.pop()
+	case *FunctionStmt:
+		e.expr(n.Name)
+		e.function(n.Func)
+	case *ReturnStmt:
+		e.exprs(n.Values)
+	case *GotoStmt:
+		e.result.addCompat("goto", CompatLuaJIT, true, false, n.Pos())
+	case *LabelStmt:
+		e.result.addCompat("::label::", CompatLuaJIT, true, false, n.Pos())
+	}
+}
+
+func (e *extractor) function(fn *FunctionExpr) {
+	e.push()
+	for _, param := range fn.Params {
+		e.scope.declare(param, nil)
+	}
+	e.block(fn.Body)
+	e.pop()
+}
+
+func (e *extractor) exprs(exprs []Expr) {
+	for _, expr := range exprs {
+		e.expr(expr)
+	}
+}
+
+func (e *extractor) expr(expr Expr) {
+	switch n := expr.(type) {
+	case *CallExpr:
+		e.result.inspectCall(n, e.scope, e.guarded > 0)
+		protected := isProtectedCall(n)
+		if protected {
+			e.guarded++
+		}
+		e.inspectCompatLibrary(n.Func, true)
+		e.callee(n.Func)
+		e.exprs(n.Args)
+		if protected {
+			e.guarded--
+		}
+	case *MethodCallExpr:
+		e.expr(n.Object)
+		e.exprs(n.Args)
+	case *IndexExpr:
+		e.result.inspectIndex(n, e.scope)
+		e.inspectCompatLibrary(n, false)
+		e.expr(n.Object)
+		e.expr(n.Key)
+
+	case *FunctionExpr:
+		e.function(n)
+	case *TableExpr:
+		for _, field := range n.Fields {
+			if field.Key != nil {
+				e.expr(field.Key)
+			}
+			e.expr(field.Value)
+		}
+	case *BinaryExpr:
+		if compatOperators[n.Op] {
+			e.result.addCompat(n.Op, CompatLua53, true, false, n.Pos())
+		}
+		if n.Op == "and" || n.Op == "or" {
+			e.probe++
+			e.expr(n.Left)
+			e.probe--
+		} else {
+			e.expr(n.Left)
+		}
+		e.expr(n.Right)
+	case *UnaryExpr:
+		if n.Op == "~" {
+			e.result.addCompat("~", CompatLua53, true, false, n.Pos())
+		}
+		e.expr(n.Operand)
+	case *ParenExpr:
+		e.expr(n.Inner)
+	}
+}
+
+func (e *extractor) callee(fn Expr) {
+	switch n := fn.(type) {
+	case *IndexExpr:
+		e.result.inspectIndex(n, e.scope)
+		e.expr(n.Object)
+		e.expr(n.Key)
+	case *NameExpr:
+	default:
+		e.expr(fn)
+	}
+}
+
+func (r *SourceResult) inspectCall(call *CallExpr, s *scope, guarded bool) {
+	name := s.qualify(call.Func)
+	r.inspectCapabilityCall(name, call, s)
+
+	switch name {
+	case "require":
+		if len(call.Args) > 0 {
+			r.inspectRequire(call.Args[0], s, guarded)
+		}
+	case "pcall", "xpcall":
+		moduleArg := 1
+		if name == "xpcall" {
+			moduleArg = 2
+		}
+		if len(call.Args) <= moduleArg {
+			break
+		}
+		switch loader := s.qualify(call.Args[0]); loader {
+		case "require":
+			r.inspectRequire(call.Args[moduleArg], s, true)
+		case "ffi.load", "package.loadlib":
+			protected := &CallExpr{Position: call.Position, Func: call.Args[0], Args: call.Args[moduleArg:]}
+			r.inspectNativeLoad(protected, loader, s, true)
+		}
+	case "import":
+		r.inspectImport(call, s)
+	case "ffi.load", "package.loadlib":
+		r.inspectNativeLoad(call, name, s, guarded)
+	case "io.open":
+		if len(call.Args) > 0 {
+			if path, ok := call.Args[0].(*StringExpr); ok {
+				r.FilePaths = append(r.FilePaths, path.Value)
+				r.addCapability(CapabilityFileAccess, path.Value, call.Pos())
+			}
+		}
+	}
+}
+
+func (r *SourceResult) inspectRequire(moduleArg Expr, s *scope, optional bool) {
+	pos := moduleArg.Pos()
+
+	if literal, ok := moduleArg.(*StringExpr); ok {
+		r.addRequire(Require{Module: literal.Value, Optional: optional}, pos)
+		return
+	}
+
+	if values := s.evalString(moduleArg); values != nil {
+		for _, value := range values {
+			r.addRequire(Require{Module: value, Inferred: true, Optional: optional}, pos)
+		}
+		return
+	}
+
+	switch arg := moduleArg.(type) {
+	case *NameExpr:
+		r.addWarning(WarningVariableRequire, arg.Name, "Dynamic require detected with variable", pos)
+	case *IndexExpr:
+		r.addWarning(WarningTableRequire, "", "Dynamic require detected with table index", pos)
+	case *BinaryExpr:
+		if arg.Op == ".." {
+			r.addWarning(WarningConcatRequire, "", "Dynamic require detected with concatenation", pos)
+		} else {
+			r.addWarning(WarningDynamicRequire, "", "Dynamic require detected", pos)
+		}
+	default:
+		r.addWarning(WarningDynamicRequire, "", "Dynamic require detected", pos)
+	}
+}
+
+func (r *SourceResult) addRequire(req Require, pos Position) {
+	module := req.Module
+	if req.Optional {
+		r.OptionalModules = append(r.OptionalModules, module)
+	} else {
+		r.RawModules = append(r.RawModules, module)
+	}
+
+	req.Module = module
+	req.Line = pos.Line
+	req.Column = pos.Column
+	r.Requires = append(r.Requires, req)
+
+	if rootModuleName(module) == "ffi" {
+		r.UsesFFI = true
+		r.addCapability(CapabilityFFI, module, pos)
+	}
+	r.inspectCapabilityModule(module, pos)
+}
+
+func (r *SourceResult) inspectIndex(index *IndexExpr, s *scope) {
+	name := s.qualify(index)
+	if name == "" {
+		return
+	}
+
+	for _, call := range networkCalls {
+		if name == call || strings.HasSuffix(name, "."+call) {
+			r.addCapability(CapabilityNetwork, name, index.Pos())
+			return
+		}
+	}
+}
+
+func (r *SourceResult) addWarning(warnType WarningType, module, message string, pos Position) {
+	r.Warnings = append(r.Warnings, Warning{
+		Type:     warnType,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Module:   module,
+		Severity: SeverityWarning,
+		Message:  message,
+	})
+}
+
+func isProtectedCall(call *CallExpr) bool {
+	switch qualifiedName(call.Func) {
+	case "pcall", "xpcall":
+		return true
+	}
+	return false
+}
+
+func qualifiedName(expr Expr) string {
+	switch e := expr.(type) {
+	case *NameExpr:
+		return e.Name
+	case *IndexExpr:
+		key, ok := e.Key.(*StringExpr)
+		if !ok {
+			return ""
+		}
+		object := qualifiedName(e.Object)
+		if object == "" {
+			return ""
+		}
+		return object + "." + key.Value
+	case *CallExpr:
+		if qualifiedName(e.Func) != "require" || len(e.Args) == 0 {
+			return ""
+		}
+		if module, ok := e.Args[0].(*StringExpr); ok {
+			return module.Value
+		}
+	case *ParenExpr:
+		return qualifiedName(e.Inner)
+	}
+
+	return ""
+}
+
+func sourceResultFromRegex(source string) *SourceResult {
+	regexResult := ParseWithRegex(source)
+
+	result := &SourceResult{
+		RawModules:        regexResult.RawModules,
+		OptionalModules:   []string{},
+		SearchPaths:       []string{},
+		NativeSearchPaths: []string{},
+		Requires:          []Require{},
+		Imports:           []Require{},
+		NativeLibraries:   []NativeLibrary{},
+		Capabilities:      []Capability{},
+		Compat:            []CompatFeature{},
+		UsesNetwork:       regexResult.UsesNetwork,
+		UsesFFI:           regexResult.UsesFFI,
+		FilePaths:         regexResult.FilePaths,
+		Warnings:          DetectDynamicRequires(source),
+	}
+
+	for _, match := range reRequireStatic.FindAllStringSubmatchIndex(source, -1) {
+		line, column := sourcePosition(source, match[2])
+		result.Requires = append(result.Requires, Require{
+			Module: source[match[2]:match[3]],
+			Line:   line,
+			Column: column,
+		})
+	}
+
+	return result
+}
+
+func sourcePosition(source string, offset int) (int, int) {
+	line := strings.Count(source[:offset], "\n") + 1
+	column := offset - strings.LastIndex(source[:offset], "\n")
+	return line, column
+}
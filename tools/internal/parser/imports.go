@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"path"
+	"strings"
+)
+
+func scriptKey(file string) string {
+	file = strings.ToLower(strings.ReplaceAll(file, "\\", "/"))
+	if idx := strings.Index(file, "moonloader/"); idx >= 0 {
+		file = file[idx+len("moonloader/"):]
+	}
+	file = strings.TrimLeft(file, "./")
+
+	for _, ext := range []string{".lua", ".luac"} {
+		if strings.HasSuffix(file, ext) {
+			return strings.TrimSuffix(file, ext)
+		}
+	}
+	return file
+}
+
+func (r *Registry) addScriptFiles(info *PackageInfo) {
+	for _, file := range info.Files {
+		switch strings.ToLower(path.Ext(file)) {
+		case ".lua", ".luac":
+		default:
+			continue
+		}
+
+		for _, key := range []string{scriptKey(file), path.Base(scriptKey(file))} {
+			if _, exists := r.scripts[key]; !exists {
+				r.scripts[key] = info.ID
+			}
+		}
+	}
+}
+
+func (r *Registry) ResolveScript(file string) string {
+	key := scriptKey(file)
+	if pkgID, ok := r.scripts[key]; ok {
+		return pkgID
+	}
+	return r.scripts[path.Base(key)]
+}
+
+func (r *SourceResult) inspectImport(call *CallExpr, s *scope) {
+	if len(call.Args) == 0 {
+		return
+	}
+
+	arg := call.Args[0]
+	pos := arg.Pos()
+
+	values := s.evalString(arg)
+	if values == nil {
+		r.addWarning(WarningDynamicImport, "", "Dynamic import detected", pos)
+		return
+	}
+
+	for _, value := range values {
+		r.Imports = append(r.Imports, Require{
+			Module:   value,
+			Inferred: len(values) > 1 || !isLiteral(arg),
+			Line:     pos.Line,
+			Column:   pos.Column,
+		})
+	}
+}
+
+func isLiteral(expr Expr) bool {
+	_, ok := expr.(*StringExpr)
+	return ok
+}
+
+func ResolveScriptImports(ctx *Context, imports []Require) (map[string]*ResolvedDependency, []Require) {
+	resolved := make(map[string]*ResolvedDependency)
+	unresolved := []Require{}
+
+	for _, imp := range imports {
+		if ctx.IsInternalModule(CanonicalModule(scriptKey(imp.Module) + ".lua")) {
+			continue
+		}
+
+		pkgID := ctx.Registry.ResolveScript(imp.Module)
+		if pkgID == "" {
+			unresolved = append(unresolved, imp)
+			continue
+		}
+
+		if strings.EqualFold(pkgID, ctx.PackageID) {
+			continue
+		}
+
+		resolved[pkgID] = &ResolvedDependency{
+			PackageID:    pkgID,
+			OriginalPath: imp.Module,
+			Resolved:     true,
+		}
+	}
+
+	return resolved, unresolved
+}
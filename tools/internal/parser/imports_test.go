@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImports(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		want     []Require
+		warnings []WarningType
+	}{
+		{
+			name:   "literal",
+			source: "local hud = import 'HUD.lua'",
+			want:   []Require{{Module: "HUD.lua", Line: 1, Column: 20}},
+		},
+		{
+			name:   "local constant",
+			source: "local file = 'moonloader/hud.lua'\nlocal hud = import(file)",
+			want:   []Require{{Module: "moonloader/hud.lua", Inferred: true, Line: 2, Column: 20}},
+		},
+		{
+			name:     "dynamic",
+			source:   "local hud = import(getName())",
+			want:     []Require{},
+			warnings: []WarningType{WarningDynamicImport},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSource(tt.source)
+			if err != nil {
+				t.Fatalf("ParseSource: %v", err)
+			}
+			if !reflect.DeepEqual(result.Imports, tt.want) {
+				t.Errorf("imports = %+v, want %+v", result.Imports, tt.want)
+			}
+			types := []WarningType{}
+			for _, w := range result.Warnings {
+				types = append(types, w.Type)
+			}
+			if tt.warnings == nil {
+				tt.warnings = []WarningType{}
+			}
+			if !reflect.DeepEqual(types, tt.warnings) {
+				t.Errorf("warnings = %v, want %v", types, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestResolveScriptImports(t *testing.T) {
+	ctx := packageContext(t, map[string]string{
+		"hud.lua":         "",
+		"hud/widgets.lua": "",
+	})
+	ctx.Registry.AddPackage(&PackageInfo{ID: "radar", Script: true, Files: []string{"Radar.lua"}})
+	ctx.Registry.AddPackage(&PackageInfo{ID: "chat-tools", Script: true, Files: []string{"lib/chat.luac"}})
+	ctx.Registry.AddPackage(&PackageInfo{ID: "hud", Script: true, Files: []string{"hud.lua"}})
+
+	tests := []struct {
+		module string
+		want   string
+	}{
+		{"radar.lua", "radar"},
+		{"moonloader\\lib\\chat.luac", "chat-tools"},
+		{"chat", "chat-tools"},
+		{"hud.lua", ""},
+		{"hud/widgets.lua", ""},
+		{"missing.lua", "unresolved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			imp := Require{Module: tt.module, Line: 1}
+			resolved, unresolved := ResolveScriptImports(ctx, []Require{imp})
+
+			got := ""
+			for pkgID, dep := range resolved {
+				got = pkgID
+				if dep.OriginalPath != tt.module || !dep.Resolved {
+					t.Errorf("dependency = %+v, want resolved from %s", dep, tt.module)
+				}
+			}
+			if len(unresolved) > 0 {
+				got = "unresolved"
+				if !reflect.DeepEqual(unresolved, []Require{imp}) {
+					t.Errorf("unresolved = %+v, want %+v", unresolved, []Require{imp})
+				}
+			}
+			if got != tt.want {
+				t.Errorf("resolved to %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type TokenType int
+
+const (
+	TokenEOF TokenType = iota
+	TokenName
+	TokenString
+	TokenNumber
+	TokenKeyword
+	TokenSymbol
+)
+
+type Token struct {
+	Type   TokenType
+	Value  string
+	Line   int
+	Column int
+	Long   bool
+}
+
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+var keywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true,
+	"end": true, "false": true, "for": true, "function": true, "goto": true,
+	"if": true, "in": true, "local": true, "nil": true, "not": true,
+	"or": true, "repeat": true, "return": true, "then": true, "true": true,
+	"until": true, "while": true,
+}
+
+var symbols = []string{
+	"...", "..", "==", "~=", "<=", ">=", "<<", ">>", "//", "::",
+	"+", "-", "*", "/", "%", "^", "#", "&", "~", "|", "<", ">", "=",
+	"(", ")", "{", "}", "[", "]", ";", ":", ",", ".",
+}
+
+type Lexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+func NewLexer(source string) *Lexer {
+	l := &Lexer{src: source, line: 1, column: 1}
+
+	if strings.HasPrefix(l.src, "\xef\xbb\xbf") {
+		l.pos = 3
+	}
+	if strings.HasPrefix(l.src[l.pos:], "#") {
+		for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+			l.pos++
+		}
+	}
+
+	return l
+}
+
+func Tokenize(source string) ([]Token, error) {
+	l := NewLexer(source)
+	tokens := []Token{}
+
+	for {
+		tok, err := l.Next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.Type == TokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *Lexer) errorf(line, column int, format string, args ...interface{}) error {
+	return &SyntaxError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (l *Lexer) peekByte(offset int) byte {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *Lexer) advance() byte {
+	c := l.src[l.pos]
+	l.pos++
+	if c == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return c
+}
+
+func (l *Lexer) Next() (Token, error) {
+	if err := l.skipSpaceAndComments(); err != nil {
+		return Token{}, err
+	}
+
+	line, column := l.line, l.column
+	if l.pos >= len(l.src) {
+		return Token{Type: TokenEOF, Line: line, Column: column}, nil
+	}
+
+	c := l.peekByte(0)
+
+	switch {
+	case isNameStart(c):
+		start := l.pos
+		for l.pos < len(l.src) && isNamePart(l.src[l.pos]) {
+			l.advance()
+		}
+		word := l.src[start:l.pos]
+		if keywords[word] {
+			return Token{Type: TokenKeyword, Value: word, Line: line, Column: column}, nil
+		}
+		return Token{Type: TokenName, Value: word, Line: line, Column: column}, nil
+
+	case isDigit(c) || (c == '.' && isDigit(l.peekByte(1))):
+		return l.readNumber(line, column)
+
+	case c == '"' || c == '\'':
+		value, err := l.readString(c)
+		if err != nil {
+			return Token{}, err
+		}
+		return Token{Type: TokenString, Value: value, Line: line, Column: column}, nil
+
+	case c == '[' && (l.peekByte(1) == '[' || l.peekByte(1) == '='):
+		if level := l.longBracketLevel(); level >= 0 {
+			value, err := l.readLongString(level)
+			if err != nil {
+				return Token{}, err
+			}
+			return Token{Type: TokenString, Value: value, Line: line, Column: column, Long: true}, nil
+		}
+	}
+
+	for _, sym := range symbols {
+		if strings.HasPrefix(l.src[l.pos:], sym) {
+			for range sym {
+				l.advance()
+			}
+			return Token{Type: TokenSymbol, Value: sym, Line: line, Column: column}, nil
+		}
+	}
+
+	return Token{}, l.errorf(line, column, "unexpected character %q", c)
+}
+
+func (l *Lexer) skipSpaceAndComments() error {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v' {
+			l.advance()
+			continue
+		}
+
+		if c == '-' && l.peekByte(1) == '-' {
+			line, column := l.line, l.column
+			l.advance()
+			l.advance()
+
+			if l.peekByte(0) == '[' {
+				if level := l.longBracketLevel(); level >= 0 {
+					if _, err := l.readLongString(level); err != nil {
+						return l.errorf(line, column, "unfinished long comment")
+					}
+					continue
+				}
+			}
+
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance()
+			}
+			continue
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (l *Lexer) longBracketLevel() int {
+	if l.peekByte(0) != '[' {
+		return -1
+	}
+
+	level := 0
+	for l.peekByte(level+1) == '=' {
+		level++
+	}
+
+	if l.peekByte(level+1) != '[' {
+		return -1
+	}
+	return level
+}
+
+func (l *Lexer) readLongString(level int) (string, error) {
+	line, column := l.line, l.column
+
+	for i := 0; i < level+2; i++ {
+		l.advance()
+	}
+
+	if l.peekByte(0) == '\r' {
+		l.advance()
+		if l.peekByte(0) == '\n' {
+			l.advance()
+		}
+	} else if l.peekByte(0) == '\n' {
+		l.advance()
+		if l.peekByte(0) == '\r' {
+			l.advance()
+		}
+	}
+
+	closing := "]" + strings.Repeat("=", level) + "]"
+	end := strings.Index(l.src[l.pos:], closing)
+	if end < 0 {
+		return "", l.errorf(line, column, "unfinished long string")
+	}
+
+	value := l.src[l.pos : l.pos+end]
+	for i := 0; i < end+len(closing); i++ {
+		l.advance()
+	}
+
+	return value, nil
+}
+
+func (l *Lexer) readString(quote byte) (string, error) {
+	line, column := l.line, l.column
+	l.advance()
+
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			return "", l.errorf(line, column, "unfinished string")
+		}
+
+		c := l.src[l.pos]
+		switch c {
+		case quote:
+			l.advance()
+			return sb.String(), nil
+		case '\n', '\r':
+			return "", l.errorf(line, column, "unfinished string")
+		case '\\':
+			l.advance()
+			if err := l.readEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(l.advance())
+		}
+	}
+}
+
+func (l *Lexer) readEscape(sb *strings.Builder) error {
+	line, column := l.line, l.column
+	if l.pos >= len(l.src) {
+		return l.errorf(line, column, "unfinished string")
+	}
+
+	c := l.advance()
+	switch c {
+	case 'a':
+		sb.WriteByte('\a')
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'v':
+		sb.WriteByte('\v')
+	case '\\', '"', '\'':
+		sb.WriteByte(c)
+	case '\n':
+		sb.WriteByte('\n')
+		if l.peekByte(0) == '\r' {
+			l.advance()
+		}
+	case '\r':
+		sb.WriteByte('\n')
+		if l.peekByte(0) == '\n' {
+			l.advance()
+		}
+	case 'x':
+		if !isHexDigit(l.peekByte(0)) || !isHexDigit(l.peekByte(1)) {
+			return l.errorf(line, column, "invalid hexadecimal escape")
+		}
+		v, _ := strconv.ParseUint(l.src[l.pos:l.pos+2], 16, 8)
+		l.advance()
+		l.advance()
+		sb.WriteByte(byte(v))
+	case 'z':
+		for l.pos < len(l.src) && isSpace(l.src[l.pos]) {
+			l.advance()
+		}
+	case 'u':
+		if l.peekByte(0) != '{' {
+			return l.errorf(line, column, "missing '{' in \\u{xxxx}")
+		}
+		l.advance()
+		start := l.pos
+		for isHexDigit(l.peekByte(0)) {
+			l.advance()
+		}
+		if l.peekByte(0) != '}' || l.pos == start {
+			return l.errorf(line, column, "invalid unicode escape")
+		}
+		v, err := strconv.ParseUint(l.src[start:l.pos], 16, 32)
+		if err != nil {
+			return l.errorf(line, column, "invalid unicode escape")
+		}
+		l.advance()
+		sb.WriteRune(rune(v))
+	default:
+		if !isDigit(c) {
+			return l.errorf(line, column, "invalid escape sequence '\\%c'", c)
+		}
+		start := l.pos - 1
+		for i := 0; i < 2 && isDigit(l.peekByte(0)); i++ {
+			l.advance()
+		}
+		v, _ := strconv.Atoi(l.src[start:l.pos])
+		if v > 255 {
+			return l.errorf(line, column, "decimal escape too large")
+		}
+		sb.WriteByte(byte(v))
+	}
+
+	return nil
+}
+
+func (l *Lexer) readNumber(line, column int) (Token, error) {
+	start := l.pos
+	exponent := "Ee"
+
+	if l.peekByte(0) == '0' && (l.peekByte(1) == 'x' || l.peekByte(1) == 'X') {
+		l.advance()
+		l.advance()
+		exponent = "Pp"
+	}
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if strings.IndexByte(exponent, c) >= 0 && (l.peekByte(1) == '+' || l.peekByte(1) == '-') {
+			l.advance()
+			l.advance()
+			continue
+		}
+		if isHexDigit(c) || c == '.' || isNamePart(c) {
+			l.advance()
+			continue
+		}
+		break
+	}
+
+	return Token{Type: TokenNumber, Value: l.src[start:l.pos], Line: line, Column: column}, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v'
+}
@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+type ModuleGraph struct {
+	Files   []string
+	Edges   map[string][]string
+	Missing []Require
+	Dynamic bool
+}
+
+func addModuleFile(files map[string]string, packageID, modulePath, relPath string) {
+	keys := []string{packageID}
+	if modulePath != "" {
+		keys = []string{modulePath, packageID + "." + modulePath}
+	}
+
+	for _, key := range keys {
+		key = strings.ToLower(key)
+		if existing, ok := files[key]; ok && !shadows(relPath, existing, key) {
+			continue
+		}
+		files[key] = relPath
+	}
+}
+
+func shadows(file, existing, key string) bool {
+	if isNativeFile(file) != isNativeFile(existing) {
+		return isNativeFile(existing)
+	}
+	return strings.ToLower(CanonicalModule(file)) == key && strings.ToLower(CanonicalModule(existing)) != key
+}
+
+func (c *Context) ModuleFile(module string) string {
+	candidates := c.ModuleCandidates(module)
+
+	for _, candidate := range candidates {
+		if file, ok := c.ModuleFiles[strings.ToLower(candidate)]; ok {
+			return file
+		}
+	}
+
+	for _, candidate := range candidates {
+		parts := strings.Split(strings.ToLower(candidate), ".")
+		for i := 1; i < len(parts); i++ {
+			if file, ok := c.ModuleFiles[strings.Join(parts[i:], ".")]; ok {
+				return file
+			}
+		}
+	}
+
+	return ""
+}
+
+func (c *Context) libraryFile(name string) string {
+	for _, file := range c.ModuleFiles {
+		if isNativeFile(file) && LibraryName(file) == name {
+			return file
+		}
+	}
+	return ""
+}
+
+func BuildModuleGraph(ctx *Context, requires []Require, libraries []NativeLibrary, warnings []Warning) *ModuleGraph {
+	graph := &ModuleGraph{
+		Files:   []string{},
+		Edges:   make(map[string][]string),
+		Missing: []Require{},
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range ctx.ModuleFiles {
+		if !seen[file] {
+			seen[file] = true
+			graph.Files = append(graph.Files, file)
+		}
+	}
+	sort.Strings(graph.Files)
+
+	for _, req := range requires {
+		if file := ctx.ModuleFile(req.Module); file != "" {
+			graph.addEdge(req.File, file)
+			continue
+		}
+
+		root := strings.Split(rootModuleName(req.Module), ".")[0]
+		if strings.EqualFold(root, ctx.PackageID) && root != req.Module {
+			graph.Missing = append(graph.Missing, req)
+		}
+	}
+
+	for _, lib := range libraries {
+		if file := ctx.libraryFile(lib.Name); file != "" {
+			graph.addEdge(lib.File, file)
+		}
+	}
+
+	for _, w := range warnings {
+		switch w.Type {
+		case WarningVariableRequire, WarningTableRequire, WarningConcatRequire:
+			graph.Dynamic = true
+		}
+	}
+
+	for file := range graph.Edges {
+		sort.Strings(graph.Edges[file])
+	}
+
+	return graph
+}
+
+func (g *ModuleGraph) addEdge(from, to string) {
+	if from == to {
+		return
+	}
+	for _, existing := range g.Edges[from] {
+		if existing == to {
+			return
+		}
+	}
+	g.Edges[from] = append(g.Edges[from], to)
+}
+
+func (g *ModuleGraph) EntryPoints(packageID string, provides []string) []string {
+	names := map[string]bool{strings.ToLower(packageID): true}
+	for _, module := range provides {
+		names[strings.ToLower(module)] = true
+	}
+
+	entries := []string{}
+	for _, file := range g.Files {
+		module := strings.ToLower(CanonicalModule(file))
+		if module == "" || names[module] || names[strings.ToLower(packageID)+"."+module] {
+			entries = append(entries, file)
+		}
+	}
+	if len(entries) > 0 {
+		return entries
+	}
+
+	required := make(map[string]bool)
+	for _, targets := range g.Edges {
+		for _, target := range targets {
+			required[target] = true
+		}
+	}
+	for _, file := range g.Files {
+		if !required[file] {
+			entries = append(entries, file)
+		}
+	}
+
+	return entries
+}
+
+func (g *ModuleGraph) Unreachable(entries []string) []string {
+	reachable := make(map[string]bool)
+	queue := append([]string{}, entries...)
+
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		if reachable[file] {
+			continue
+		}
+		reachable[file] = true
+		queue = append(queue, g.Edges[file]...)
+	}
+
+	unreachable := []string{}
+	for _, file := range g.Files {
+		if !reachable[file] {
+			unreachable = append(unreachable, file)
+		}
+	}
+
+	return unreachable
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModuleGraph(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		edges       map[string][]string
+		missing     []string
+		dynamic     bool
+		entries     []string
+		unreachable []string
+	}{
+		{
+			name: "single file",
+			files: map[string]string{
+				"hud.lua": "print('hud')",
+			},
+			edges:       map[string][]string{},
+			missing:     []string{},
+			entries:     []string{"hud.lua"},
+			unreachable: []string{},
+		},
+		{
+			name: "submodules",
+			files: map[string]string{
+				"hud.lua":         "local util = require('hud.util')\nlocal cfg = require 'hud.config'",
+				"hud/util.lua":    "local cfg = require('hud.config')\nlocal lfs = require('lfs')",
+				"hud/config.lua":  "return {}",
+				"hud/orphan.lua":  "return {}",
+				"hud/missing.lua": "local x = require('hud.gone')",
+			},
+			edges: map[string][]string{
+				"hud.lua":      {"hud/config.lua", "hud/util.lua"},
+				"hud/util.lua": {"hud/config.lua"},
+			},
+			missing:     []string{"hud.gone"},
+			entries:     []string{"hud.lua"},
+			unreachable: []string{"hud/missing.lua", "hud/orphan.lua"},
+		},
+		{
+			name: "entry points from edges",
+			files: map[string]string{
+				"core.lua":  "local util = require('util')",
+				"util.lua":  "return {}",
+				"extra.lua": "return {}",
+			},
+			edges: map[string][]string{
+				"core.lua": {"util.lua"},
+			},
+			missing:     []string{},
+			entries:     []string{"core.lua", "extra.lua"},
+			unreachable: []string{},
+		},
+		{
+			name: "dynamic require",
+			files: map[string]string{
+				"hud.lua":        "local name = 'hud.' .. mode\nlocal m = require(name)",
+				"hud/plugin.lua": "return {}",
+			},
+			edges:       map[string][]string{},
+			missing:     []string{},
+			dynamic:     true,
+			entries:     []string{"hud.lua"},
+			unreachable: []string{"hud/plugin.lua"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := packageContext(t, tt.files)
+			analysis, err := AnalyzeWithContext(ctx, ctx.PackagePath)
+			if err != nil {
+				t.Fatalf("AnalyzeWithContext: %v", err)
+			}
+			graph := analysis.ModuleGraph
+
+			if !reflect.DeepEqual(graph.Edges, tt.edges) {
+				t.Errorf("edges = %v, want %v", graph.Edges, tt.edges)
+			}
+			missing := []string{}
+			for _, req := range graph.Missing {
+				missing = append(missing, req.Module)
+			}
+			if !reflect.DeepEqual(missing, tt.missing) {
+				t.Errorf("missing = %v, want %v", missing, tt.missing)
+			}
+			if graph.Dynamic != tt.dynamic {
+				t.Errorf("dynamic = %v, want %v", graph.Dynamic, tt.dynamic)
+			}
+
+			entries := graph.EntryPoints(ctx.PackageID, nil)
+			if !reflect.DeepEqual(entries, tt.entries) {
+				t.Errorf("entry points = %v, want %v", entries, tt.entries)
+			}
+			if unreachable := graph.Unreachable(entries); !reflect.DeepEqual(unreachable, tt.unreachable) {
+				t.Errorf("unreachable = %v, want %v", unreachable, tt.unreachable)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+type NativeLibrary struct {
+	Name     string
+	Loader   string
+	Optional bool
+	Dynamic  bool
+	File     string
+	Line     int
+	Column   int
+}
+
+var systemLibraries = map[string]bool{
+	"advapi32":    true,
+	"bcrypt":      true,
+	"comctl32":    true,
+	"comdlg32":    true,
+	"crypt32":     true,
+	"d3d9":        true,
+	"d3dx9_43":    true,
+	"dbghelp":     true,
+	"dinput8":     true,
+	"dsound":      true,
+	"dwmapi":      true,
+	"dxgi":        true,
+	"gdi32":       true,
+	"gdiplus":     true,
+	"imm32":       true,
+	"iphlpapi":    true,
+	"kernel32":    true,
+	"msvcrt":      true,
+	"ntdll":       true,
+	"ole32":       true,
+	"oleaut32":    true,
+	"opengl32":    true,
+	"psapi":       true,
+	"secur32":     true,
+	"setupapi":    true,
+	"shell32":     true,
+	"shlwapi":     true,
+	"urlmon":      true,
+	"user32":      true,
+	"version":     true,
+	"winhttp":     true,
+	"wininet":     true,
+	"winmm":       true,
+	"ws2_32":      true,
+	"xinput1_3":   true,
+	"xinput9_1_0": true,
+}
+
+func LibraryName(file string) string {
+	base := path.Base(strings.ReplaceAll(file, "\\", "/"))
+	for _, ext := range []string{".dll", ".so"} {
+		if strings.HasSuffix(strings.ToLower(base), ext) {
+			base = base[:len(base)-len(ext)]
+			break
+		}
+	}
+	return strings.ToLower(base)
+}
+
+func IsSystemLibrary(name string) bool {
+	return systemLibraries[LibraryName(name)]
+}
+
+func (r *SourceResult) inspectNativeLoad(call *CallExpr, loader string, s *scope, guarded bool) {
+	if len(call.Args) == 0 {
+		return
+	}
+
+	arg := call.Args[0]
+	pos := arg.Pos()
+
+	names := s.evalString(arg)
+	if names == nil {
+		template := s.evalTemplate(arg)
+		if idx := strings.LastIndex(template, unknownPart); idx >= 0 {
+			template = template[idx+len(unknownPart):]
+		}
+		if strings.Trim(template, "/\\.") != "" {
+			names = []string{template}
+		}
+	}
+
+	if names == nil {
+		r.addWarning(WarningDynamicNativeLibrary, "", "Dynamic native library load detected", pos)
+		return
+	}
+
+	for _, name := range names {
+		r.NativeLibraries = append(r.NativeLibraries, NativeLibrary{
+			Name:     LibraryName(name),
+			Loader:   loader,
+			Optional: guarded,
+			Line:     pos.Line,
+			Column:   pos.Column,
+		})
+	}
+}
+
+func (r *Registry) ResolveLibrary(name string) string {
+	providers := r.libraries[LibraryName(name)]
+	if len(providers) == 0 {
+		return ""
+	}
+
+	for _, pkgID := range providers {
+		if strings.EqualFold(pkgID, name) {
+			return pkgID
+		}
+	}
+
+	sorted := append([]string{}, providers...)
+	sort.Strings(sorted)
+	return sorted[0]
+}
+
+func ResolveNativeLibraries(ctx *Context, libraries []NativeLibrary) (map[string]*ResolvedDependency, []NativeLibrary) {
+	resolved := make(map[string]*ResolvedDependency)
+	unresolved := []NativeLibrary{}
+
+	for _, lib := range libraries {
+		if ctx.NativeFiles[lib.Name] || IsSystemLibrary(lib.Name) {
+			continue
+		}
+
+		pkgID := ctx.Registry.ResolveLibrary(lib.Name)
+		if pkgID == "" {
+			unresolved = append(unresolved, lib)
+			continue
+		}
+
+		if strings.EqualFold(pkgID, ctx.PackageID) {
+			continue
+		}
+
+		resolved[pkgID] = &ResolvedDependency{
+			PackageID:    pkgID,
+			OriginalPath: lib.Name,
+			Resolved:     true,
+		}
+	}
+
+	return resolved, unresolved
+}
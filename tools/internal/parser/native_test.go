@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNativeLibraryLoads(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []NativeLibrary
+	}{
+		{
+			name:   "ffi.load",
+			source: "local ffi = require 'ffi'\nlocal lib = ffi.load('bass')",
+			want:   []NativeLibrary{{Name: "bass", Loader: "ffi.load", Line: 2, Column: 22}},
+		},
+		{
+			name:   "package.loadlib",
+			source: `local open = package.loadlib("lib\\lfs.dll", "luaopen_lfs")`,
+			want:   []NativeLibrary{{Name: "lfs", Loader: "package.loadlib", Line: 1, Column: 30}},
+		},
+		{
+			name:   "pcall ffi.load",
+			source: "local ffi = require 'ffi'\nlocal ok, lib = pcall(ffi.load, 'bass')",
+			want:   []NativeLibrary{{Name: "bass", Loader: "ffi.load", Optional: true, Line: 2, Column: 33}},
+		},
+		{
+			name:   "pcall package.loadlib",
+			source: `local ok, open = pcall(package.loadlib, "lfs.dll", "luaopen_lfs")`,
+			want:   []NativeLibrary{{Name: "lfs", Loader: "package.loadlib", Optional: true, Line: 1, Column: 41}},
+		},
+		{
+			name:   "xpcall ffi.load",
+			source: "local ffi = require 'ffi'\nxpcall(ffi.load, print, 'bass')",
+			want:   []NativeLibrary{{Name: "bass", Loader: "ffi.load", Optional: true, Line: 2, Column: 25}},
+		},
+		{
+			name:   "ffi.load inside pcall function",
+			source: "local ffi = require 'ffi'\npcall(function() return ffi.load('bass') end)",
+			want:   []NativeLibrary{{Name: "bass", Loader: "ffi.load", Optional: true, Line: 2, Column: 34}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSource(tt.source)
+			if err != nil {
+				t.Fatalf("ParseSource: %v", err)
+			}
+			if !reflect.DeepEqual(result.NativeLibraries, tt.want) {
+				t.Errorf("native libraries = %+v, want %+v", result.NativeLibraries, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	maxLineTokens     = 1000
+	minEntropyStrings = 16
+	minEntropyBytes   = 512
+	maxStringEntropy  = 6.5
+)
+
+var bytecodeHeaders = []string{
+	"\x1bLJ",
+	"\x1bLua",
+}
+
+func IsBytecode(source string) bool {
+	for _, header := range bytecodeHeaders {
+		if strings.HasPrefix(source, header) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t WarningType) IsOpacity() bool {
+	switch t {
+	case WarningBytecode, WarningObfuscatedLine, WarningHighEntropyStrings:
+		return true
+	}
+	return false
+}
+
+func bytecodeResult() *SourceResult {
+	return &SourceResult{
+		RawModules:        []string{},
+		OptionalModules:   []string{},
+		SearchPaths:       []string{},
+		NativeSearchPaths: []string{},
+		Requires:          []Require{},
+		Imports:           []Require{},
+		NativeLibraries:   []NativeLibrary{},
+		Capabilities:      []Capability{},
+		Compat:            []CompatFeature{},
+		FilePaths:         []string{},
+		Warnings: []Warning{{
+			Type:     WarningBytecode,
+			Line:     1,
+			Column:   1,
+			Severity: SeverityError,
+			Message:  "Precompiled Lua bytecode, source cannot be analyzed",
+		}},
+	}
+}
+
+func detectLongLines(source string) []Warning {
+	tokens, err := Tokenize(source)
+	if err != nil {
+		return nil
+	}
+
+	counts := make(map[int]int)
+	lines := []int{}
+	for _, tok := range tokens {
+		if tok.Type == TokenString || tok.Type == TokenEOF {
+			continue
+		}
+		counts[tok.Line]++
+		if counts[tok.Line] == maxLineTokens {
+			lines = append(lines, tok.Line)
+		}
+	}
+
+	warnings := []Warning{}
+	for _, line := range lines {
+		warnings = append(warnings, Warning{
+			Type:     WarningObfuscatedLine,
+			Line:     line,
+			Column:   1,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("Line contains %d tokens, source looks minified or obfuscated", counts[line]),
+		})
+	}
+
+	return warnings
+}
+
+func detectHighEntropyTables(chunk *Chunk) []Warning {
+	warnings := []Warning{}
+
+	Inspect(chunk.Block, func(node Node) bool {
+		table, ok := node.(*TableExpr)
+		if !ok {
+			return true
+		}
+
+		pool := []byte{}
+		count := 0
+		for _, field := range table.Fields {
+			if str, ok := field.Value.(*StringExpr); ok {
+				pool = append(pool, str.Value...)
+				count++
+			}
+		}
+
+		if count < minEntropyStrings || len(pool) < minEntropyBytes {
+			return true
+		}
+
+		if entropy := shannonEntropy(pool); entropy >= maxStringEntropy {
+			pos := table.Pos()
+			warnings = append(warnings, Warning{
+				Type:     WarningHighEntropyStrings,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("Table of %d strings has %.2f bits/byte entropy, contents look encrypted", count, entropy),
+			})
+			return false
+		}
+
+		return true
+	})
+
+	return warnings
+}
+
+func shannonEntropy(data []byte) float64 {
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+
+	entropy := 0.0
+	for _, n := range counts {
+		if n == 0 {
+			continue
+		}
+		p := float64(n) / float64(len(data))
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestIsBytecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		{"luajit", "\x1bLJ\x02\x00", true},
+		{"lua", "\x1bLua\x51\x00", true},
+		{"source", "local a = 1", false},
+		{"escape later in the file", "local a = '\x1bLJ'", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBytecode(tt.source); got != tt.want {
+				t.Errorf("IsBytecode(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLongLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []int
+	}{
+		{"short lines", "local a = 1\nlocal b = 2", []int{}},
+		{"just under the limit", strings.Repeat("a=1 ", 333), []int{}},
+		{"at the limit", "local x\n" + strings.Repeat("a=1 ", 333) + "b", []int{2}},
+		{"strings are not counted", "f" + strings.Repeat("'x'", 1200), []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []int{}
+			for _, w := range detectLongLines(tt.source) {
+				if w.Type != WarningObfuscatedLine {
+					t.Errorf("warning type = %v, want %v", w.Type, WarningObfuscatedLine)
+				}
+				got = append(got, w.Line)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("lines = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectHighEntropyTables(t *testing.T) {
+	// Every byte value appears equally often, so the pool has 8 bits/byte.
+	uniform := func(count, size int) string {
+		fields := []string{}
+		for i := 0; i < count; i++ {
+			var b strings.Builder
+			for j := 0; j < size; j++ {
+				fmt.Fprintf(&b, "\\%d", (i*size+j*37)%256)
+			}
+			fields = append(fields, "'"+b.String()+"'")
+		}
+		return "local t = {" + strings.Join(fields, ", ") + "}"
+	}
+	repeated := func(count, size int) string {
+		fields := []string{}
+		for i := 0; i < count; i++ {
+			fields = append(fields, "'"+strings.Repeat("ab", size/2)+"'")
+		}
+		return "local t = {" + strings.Join(fields, ", ") + "}"
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   int
+	}{
+		{"encrypted strings", uniform(16, 64), 1},
+		{"too few strings", uniform(15, 64), 0},
+		{"too few bytes", uniform(16, 16), 0},
+		{"plain strings", repeated(16, 64), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunk, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			warnings := detectHighEntropyTables(chunk)
+			if len(warnings) != tt.want {
+				t.Fatalf("warnings = %+v, want %d", warnings, tt.want)
+			}
+			for _, w := range warnings {
+				if w.Type != WarningHighEntropyStrings || w.Line != 1 || w.Column != 11 {
+					t.Errorf("warning = %+v, want high-entropy strings at 1:11", w)
+				}
+			}
+		})
+	}
+}
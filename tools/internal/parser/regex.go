@@ -2,7 +2,6 @@ package parser
 
 import (
 	"regexp"
-	"strings"
 )
 
 var (
@@ -28,8 +27,7 @@ func ParseWithRegex(source string) *RegexResult {
 	matches := reRequireStatic.FindAllStringSubmatch(source, -1)
 	for _, match := range matches {
 		if len(match) > 1 {
-			module := strings.TrimPrefix(match[1], "lib.")
-			result.RawModules = append(result.RawModules, module)
+			result.RawModules = append(result.RawModules, match[1])
 		}
 	}
 
@@ -3,15 +3,32 @@ package parser
 import (
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 type Analysis struct {
-	Dependencies []string
-	FilePaths    []string
-	UsesNetwork  bool
-	UsesFFI      bool
-	Warnings     []Warning
-	HasDynamic   bool
+	Dependencies         []string
+	OptionalDependencies []string
+	ScriptDependencies   []string
+	SearchPaths          []string
+	NativeSearchPaths    []string
+	Requires             []Require
+	Imports              []Require
+	NativeLibraries      []NativeLibrary
+	Capabilities         []Capability
+	Compat               []CompatFeature
+	LuaCompat            CompatLevel
+	ModuleGraph          *ModuleGraph
+	Unresolved           []UnresolvedDependency
+	FilePaths            []string
+	UsesNetwork          bool
+	UsesFFI              bool
+	Warnings             []Warning
+	Findings             map[string][]Warning
+	HasDynamic           bool
+	Precompiled          bool
+	Obfuscated           bool
 }
 
 func AnalyzeLua(sourcePath string, excludeID string, availableDeps map[string]bool) (*Analysis, error) {
@@ -32,9 +49,23 @@ func AnalyzeLua(sourcePath string, excludeID string, availableDeps map[string]bo
 
 func AnalyzeWithContext(ctx *Context, sourcePath string) (*Analysis, error) {
 	analysis := &Analysis{
-		Dependencies: []string{},
-		FilePaths:    []string{},
-		Warnings:     []Warning{},
+		Dependencies:         []string{},
+		OptionalDependencies: []string{},
+		ScriptDependencies:   []string{},
+		SearchPaths:          []string{},
+		NativeSearchPaths:    []string{},
+		Requires:             []Require{},
+		Imports:              []Require{},
+		NativeLibraries:      []NativeLibrary{},
+		Capabilities:         []Capability{},
+		Compat:               []CompatFeature{},
+		Unresolved:           []UnresolvedDependency{},
+		FilePaths:            []string{},
+		Warnings:             []Warning{},
+		Findings:             make(map[string][]Warning),
+	}
+	for _, a := range ctx.Analyzers {
+		analysis.Findings[a.Name()] = []Warning{}
 	}
 
 	luaFiles, err := findLuaFiles(sourcePath)
@@ -43,6 +74,7 @@ func AnalyzeWithContext(ctx *Context, sourcePath string) (*Analysis, error) {
 	}
 
 	allRawModules := []string{}
+	allOptionalModules := []string{}
 
 	for _, file := range luaFiles {
 		content, err := os.ReadFile(file)
@@ -50,24 +82,90 @@ func AnalyzeWithContext(ctx *Context, sourcePath string) (*Analysis, error) {
 			continue
 		}
 
-		contentStr := string(content)
+		relPath := relativeFile(sourcePath, file)
+		fileAnalysis := ctx.analyzeFile(relPath, content)
+		result := fileAnalysis.Result
+
+		allRawModules = append(allRawModules, result.RawModules...)
+		allOptionalModules = append(allOptionalModules, result.OptionalModules...)
+		analysis.SearchPaths = mergeTemplates(analysis.SearchPaths, result.SearchPaths)
+		analysis.NativeSearchPaths = mergeTemplates(analysis.NativeSearchPaths, result.NativeSearchPaths)
+		analysis.FilePaths = append(analysis.FilePaths, result.FilePaths...)
 
-		regexResult := ParseWithRegex(contentStr)
-		allRawModules = append(allRawModules, regexResult.RawModules...)
-		analysis.FilePaths = append(analysis.FilePaths, regexResult.FilePaths...)
+		for _, req := range result.Requires {
+			req.File = relPath
+			analysis.Requires = append(analysis.Requires, req)
+		}
+		for _, imp := range result.Imports {
+			imp.File = relPath
+			analysis.Imports = append(analysis.Imports, imp)
+		}
+		for _, lib := range result.NativeLibraries {
+			lib.File = relPath
+			analysis.NativeLibraries = append(analysis.NativeLibraries, lib)
+		}
+		for _, capability := range result.Capabilities {
+			capability.File = relPath
+			analysis.Capabilities = append(analysis.Capabilities, capability)
+		}
+		for _, feature := range result.Compat {
+			feature.File = relPath
+			analysis.Compat = append(analysis.Compat, feature)
+		}
 
-		if regexResult.UsesNetwork {
+		if result.UsesNetwork {
 			analysis.UsesNetwork = true
 		}
-		if regexResult.UsesFFI {
+		if result.UsesFFI {
 			analysis.UsesFFI = true
 		}
 
-		warnings := DetectDynamicRequires(contentStr)
-		analysis.Warnings = append(analysis.Warnings, warnings...)
-		if len(warnings) > 0 {
-			analysis.HasDynamic = true
+		warnings := []Warning{}
+		for _, w := range result.Warnings {
+			switch {
+			case w.Type == WarningBytecode:
+				analysis.Precompiled = true
+			case !w.Type.IsDynamicRequire():
+				warnings = append(warnings, w)
+			}
+		}
+		for _, a := range ctx.Analyzers {
+			for _, w := range fileAnalysis.Findings[a.Name()] {
+				w.File = relPath
+				analysis.Findings[a.Name()] = append(analysis.Findings[a.Name()], w)
+				warnings = append(warnings, w)
+			}
+		}
+
+		for _, w := range warnings {
+			w.File = relPath
+			analysis.Warnings = append(analysis.Warnings, w)
+			switch {
+			case w.Type == WarningBytecode:
+				analysis.Precompiled = true
+			case w.Type.IsOpacity():
+				analysis.Obfuscated = true
+			case w.Type.IsDynamic():
+				analysis.HasDynamic = true
+			}
+		}
+	}
+
+	analysis.LuaCompat = MaxCompatLevel(analysis.Compat)
+
+	ctx.AddSearchPaths(analysis.SearchPaths, analysis.NativeSearchPaths)
+	analysis.ModuleGraph = BuildModuleGraph(ctx, analysis.Requires, analysis.NativeLibraries, analysis.Warnings)
+
+	if ctx.Trace != nil {
+		provides := []string{}
+		if pkg := ctx.Registry.GetPackage(ctx.PackageID); pkg != nil {
+			provides = pkg.Provides
 		}
+		trace := Trace(ctx, analysis.ModuleGraph.EntryPoints(ctx.PackageID, provides), ctx.Trace)
+		raw, optional := analysis.mergeTrace(trace)
+		allRawModules = append(allRawModules, raw...)
+		allOptionalModules = append(allOptionalModules, optional...)
+		analysis.ModuleGraph = BuildModuleGraph(ctx, analysis.Requires, analysis.NativeLibraries, analysis.Warnings)
 	}
 
 	resolved := ResolveDependencies(ctx, allRawModules)
@@ -76,9 +174,145 @@ func AnalyzeWithContext(ctx *Context, sourcePath string) (*Analysis, error) {
 		analysis.Dependencies = append(analysis.Dependencies, pkgID)
 	}
 
+	analysis.Unresolved = UnresolvedRequires(ctx, analysis.Requires)
+	for _, dep := range analysis.Unresolved {
+		message := "Unresolved module: no package in the registry provides " + dep.Module
+		if len(dep.Suggestions) > 0 {
+			message += " (did you mean " + strings.Join(dep.Suggestions, ", ") + "?)"
+		}
+		severity := SeverityWarning
+		if dep.Optional {
+			severity = SeverityInfo
+		}
+		analysis.Warnings = append(analysis.Warnings, Warning{
+			Type:     WarningUnresolvedModule,
+			File:     dep.File,
+			Line:     dep.Line,
+			Column:   dep.Column,
+			Module:   dep.Module,
+			Severity: severity,
+			Message:  message,
+		})
+	}
+
+	requiredLibs := []NativeLibrary{}
+	optionalLibs := []NativeLibrary{}
+	for _, lib := range analysis.NativeLibraries {
+		if lib.Optional {
+			optionalLibs = append(optionalLibs, lib)
+		} else {
+			requiredLibs = append(requiredLibs, lib)
+		}
+	}
+
+	nativeDeps, unresolved := ResolveNativeLibraries(ctx, requiredLibs)
+	for pkgID, dep := range nativeDeps {
+		if _, ok := resolved[pkgID]; !ok {
+			resolved[pkgID] = dep
+			analysis.Dependencies = append(analysis.Dependencies, pkgID)
+		}
+	}
+
+	optional := ResolveDependencies(ctx, allOptionalModules)
+	optionalNativeDeps, optionalUnresolved := ResolveNativeLibraries(ctx, optionalLibs)
+	for pkgID, dep := range optionalNativeDeps {
+		optional[pkgID] = dep
+	}
+
+	for pkgID := range optional {
+		if _, ok := resolved[pkgID]; ok {
+			continue
+		}
+		analysis.OptionalDependencies = append(analysis.OptionalDependencies, pkgID)
+	}
+
+	for _, lib := range append(unresolved, optionalUnresolved...) {
+		severity := SeverityWarning
+		if lib.Optional {
+			severity = SeverityInfo
+		}
+		analysis.Warnings = append(analysis.Warnings, Warning{
+			Type:     WarningUnresolvedNativeLibrary,
+			File:     lib.File,
+			Line:     lib.Line,
+			Column:   lib.Column,
+			Module:   lib.Name,
+			Severity: severity,
+			Message:  "Unresolved native library: no package ships " + lib.Name,
+		})
+	}
+
+	scriptDeps, unresolvedImports := ResolveScriptImports(ctx, analysis.Imports)
+	for pkgID := range scriptDeps {
+		analysis.ScriptDependencies = append(analysis.ScriptDependencies, pkgID)
+	}
+	for _, imp := range unresolvedImports {
+		analysis.Warnings = append(analysis.Warnings, Warning{
+			Type:     WarningUnresolvedImport,
+			File:     imp.File,
+			Line:     imp.Line,
+			Column:   imp.Column,
+			Module:   imp.Module,
+			Severity: SeverityWarning,
+			Message:  "Unresolved import: no script in the registry provides " + imp.Module,
+		})
+	}
+
+	sort.Strings(analysis.Dependencies)
+	sort.Strings(analysis.OptionalDependencies)
+	sort.Strings(analysis.ScriptDependencies)
+	sort.SliceStable(analysis.Compat, func(i, j int) bool {
+		a, b := analysis.Compat[i], analysis.Compat[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Feature < b.Feature
+	})
+
 	return analysis, nil
 }
 
+func parseFile(source string) (*SourceResult, *Chunk) {
+	if IsBytecode(source) {
+		return bytecodeResult(), nil
+	}
+
+	chunk, err := Parse(source)
+	if err == nil {
+		return ExtractFromAST(chunk), chunk
+	}
+
+	result := sourceResultFromRegex(source)
+
+	warning := Warning{
+		Type:     WarningParseError,
+		Severity: SeverityInfo,
+		Message:  "Failed to parse Lua source, falling back to regex analysis: " + err.Error(),
+	}
+	if syntaxErr, ok := err.(*SyntaxError); ok {
+		warning.Line = syntaxErr.Line
+		warning.Column = syntaxErr.Column
+		warning.Message = "Failed to parse Lua source, falling back to regex analysis: " + syntaxErr.Message
+	}
+	result.Warnings = append(result.Warnings, warning)
+
+	return result, nil
+}
+
+func relativeFile(root, file string) string {
+	relPath, err := filepath.Rel(root, file)
+	if err != nil || relPath == "." {
+		return filepath.Base(file)
+	}
+	return filepath.ToSlash(relPath)
+}
+
 func findLuaFiles(path string) ([]string, error) {
 	var files []string
 
@@ -88,7 +322,7 @@ func findLuaFiles(path string) ([]string, error) {
 	}
 
 	if !info.IsDir() {
-		if filepath.Ext(path) == ".lua" {
+		if isLuaSource(path) {
 			return []string{path}, nil
 		}
 		return []string{}, nil
@@ -98,7 +332,7 @@ func findLuaFiles(path string) ([]string, error) {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() && filepath.Ext(p) == ".lua" {
+		if !info.IsDir() && isLuaSource(p) {
 			files = append(files, p)
 		}
 		return nil
@@ -106,3 +340,8 @@ func findLuaFiles(path string) ([]string, error) {
 
 	return files, err
 }
+
+func isLuaSource(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".lua" || ext == ".luac"
+}
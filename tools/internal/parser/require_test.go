@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeWithContextIsStable(t *testing.T) {
+	ctx := packageContext(t, map[string]string{
+		"hud.lua": `
+local vkeys = require 'vkeys'
+local imgui = require 'mimgui'
+local ok, json = pcall(require, 'cjson')
+local ok2, lfs = pcall(require, 'lfs')
+local inicfg = require 'inicfg'
+local a = 1 // 2
+local b = 1 << 2
+`,
+		"hud/util.lua": "local memory = require 'memory'\nlocal c = ~1",
+	})
+	for _, id := range []string{"vkeys", "mimgui", "cjson", "lfs", "inicfg", "memory"} {
+		ctx.Registry.AddPackage(&PackageInfo{ID: id})
+	}
+
+	var first *Analysis
+	for i := 0; i < 10; i++ {
+		analysis, err := AnalyzeWithContext(ctx, ctx.PackagePath)
+		if err != nil {
+			t.Fatalf("AnalyzeWithContext: %v", err)
+		}
+		if first == nil {
+			first = analysis
+			continue
+		}
+		if !reflect.DeepEqual(analysis.Dependencies, first.Dependencies) ||
+			!reflect.DeepEqual(analysis.OptionalDependencies, first.OptionalDependencies) ||
+			!reflect.DeepEqual(analysis.Compat, first.Compat) {
+			t.Fatalf("run %d differs from the first run", i)
+		}
+	}
+
+	if want := []string{"inicfg", "memory", "mimgui", "vkeys"}; !reflect.DeepEqual(first.Dependencies, want) {
+		t.Errorf("dependencies = %v, want %v", first.Dependencies, want)
+	}
+	if want := []string{"cjson", "lfs"}; !reflect.DeepEqual(first.OptionalDependencies, want) {
+		t.Errorf("optional dependencies = %v, want %v", first.OptionalDependencies, want)
+	}
+
+	features := []string{}
+	for _, f := range first.Compat {
+		features = append(features, f.File+" "+f.Feature)
+	}
+	if want := []string{"hud.lua //", "hud.lua <<", "hud/util.lua ~"}; !reflect.DeepEqual(features, want) {
+		t.Errorf("compat = %v, want %v", features, want)
+	}
+}
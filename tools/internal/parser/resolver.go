@@ -14,36 +14,58 @@ func ResolveDependencies(ctx *Context, rawModules []string) map[string]*Resolved
 	resolved := make(map[string]*ResolvedDependency)
 
 	for _, module := range rawModules {
-		if ctx.IsInternalModule(module) {
+		pkgID := ctx.ResolvePackage(module)
+		if pkgID == "" {
 			continue
 		}
 
-		parts := strings.Split(module, ".")
-		rootModule := parts[0]
-
-		if strings.EqualFold(rootModule, ctx.PackageID) {
-			continue
+		resolved[pkgID] = &ResolvedDependency{
+			PackageID:    pkgID,
+			OriginalPath: module,
+			Resolved:     ctx.Registry.GetPackage(pkgID) != nil,
 		}
+	}
 
-		if isBuiltin(rootModule) {
-			continue
-		}
+	return resolved
+}
 
-		pkgID := ctx.Registry.ResolveModule(module)
-		if pkgID == "" {
-			pkgID = rootModule
+func (c *Context) ResolvePackage(module string) string {
+	candidates := c.ModuleCandidates(module)
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	for _, candidate := range candidates {
+		if c.IsInternalModule(candidate) {
+			return ""
 		}
+	}
+
+	rootModule := strings.Split(rootModuleName(module), ".")[0]
+
+	if strings.EqualFold(rootModule, c.PackageID) {
+		return ""
+	}
+
+	if isBuiltin(rootModule) {
+		return ""
+	}
 
-		if pkgID != "" && !strings.EqualFold(pkgID, ctx.PackageID) {
-			resolved[pkgID] = &ResolvedDependency{
-				PackageID:    pkgID,
-				OriginalPath: module,
-				Resolved:     ctx.Registry.GetPackage(pkgID) != nil,
-			}
+	pkgID := ""
+	for _, candidate := range candidates {
+		if pkgID = c.Registry.ResolveModule(candidate); pkgID != "" {
+			break
 		}
 	}
+	if pkgID == "" {
+		pkgID = rootModule
+	}
 
-	return resolved
+	if strings.EqualFold(pkgID, c.PackageID) {
+		return ""
+	}
+
+	return pkgID
 }
 
 var builtinModules = map[string]bool{
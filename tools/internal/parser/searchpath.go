@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"path"
+	"strings"
+)
+
+const unknownPart = "\x00"
+
+var DefaultSearchPaths = []string{
+	"lib/?.lua",
+	"lib/?/init.lua",
+	"?.lua",
+	"?/init.lua",
+	"lib/?.luac",
+	"lib/?/init.luac",
+	"?.luac",
+	"?/init.luac",
+}
+
+var DefaultNativeSearchPaths = []string{
+	"lib/?.dll",
+	"?.dll",
+}
+
+var moduleExtensions = []string{".lua", ".luac", ".dll", ".so"}
+
+func (s *scope) evalTemplate(expr Expr) string {
+	switch e := expr.(type) {
+	case *StringExpr:
+		return e.Value
+	case *ParenExpr:
+		return s.evalTemplate(e.Inner)
+	case *BinaryExpr:
+		if e.Op == ".." {
+			return s.evalTemplate(e.Left) + s.evalTemplate(e.Right)
+		}
+	case *NameExpr:
+		if values := s.evalString(e); len(values) == 1 {
+			return values[0]
+		}
+		if b := s.lookup(e.Name); b != nil && b.template != "" {
+			return b.template
+		}
+	}
+
+	return unknownPart
+}
+
+func (s *scope) evalSearchPaths(expr Expr) []string {
+	templates := []string{}
+
+	for _, entry := range strings.Split(s.evalTemplate(expr), ";") {
+		if !strings.Contains(entry, "?") {
+			continue
+		}
+
+		if idx := strings.LastIndex(entry, unknownPart); idx >= 0 {
+			entry = entry[idx+len(unknownPart):]
+		}
+
+		if template := normalizeTemplate(entry); template != "" {
+			templates = append(templates, template)
+		}
+	}
+
+	return templates
+}
+
+func normalizeTemplate(template string) string {
+	template = strings.ReplaceAll(template, "\\", "/")
+
+	if idx := strings.Index(strings.ToLower(template), "moonloader/"); idx >= 0 {
+		template = template[idx+len("moonloader/"):]
+	}
+
+	template = strings.TrimLeft(template, "./")
+	if template == "" || strings.Contains(template, ":") {
+		return ""
+	}
+
+	return path.Clean(template)
+}
+
+func CanonicalModule(file string) string {
+	templates := append(append([]string{}, DefaultSearchPaths...), DefaultNativeSearchPaths...)
+	return ModuleName(file, templates)
+}
+
+func ModuleName(file string, templates []string) string {
+	file = strings.TrimLeft(strings.ReplaceAll(file, "\\", "/"), "./")
+
+	module := ""
+	for _, template := range templates {
+		name, ok := matchTemplate(file, template)
+		if !ok {
+			continue
+		}
+		if module == "" || strings.Count(name, "/") < strings.Count(module, "/") {
+			module = name
+		}
+	}
+
+	if module == "" {
+		module = file
+		for _, ext := range moduleExtensions {
+			if strings.HasSuffix(module, ext) {
+				module = strings.TrimSuffix(module, ext)
+				break
+			}
+		}
+	}
+
+	if module == "init" {
+		return ""
+	}
+
+	return strings.ReplaceAll(module, "/", ".")
+}
+
+func matchTemplate(file, template string) (string, bool) {
+	prefix, suffix, ok := strings.Cut(template, "?")
+	if !ok || !strings.HasPrefix(file, prefix) || !strings.HasSuffix(file, suffix) {
+		return "", false
+	}
+	if len(file) <= len(prefix)+len(suffix) {
+		return "", false
+	}
+
+	name := file[len(prefix) : len(file)-len(suffix)]
+	if strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+func rootModuleName(module string) string {
+	return CanonicalModule(strings.ReplaceAll(module, ".", "/") + ".lua")
+}
+
+func (c *Context) AddSearchPaths(luaPaths, nativePaths []string) {
+	c.SearchPaths = mergeTemplates(luaPaths, c.SearchPaths)
+	c.NativeSearchPaths = mergeTemplates(nativePaths, c.NativeSearchPaths)
+}
+
+func (c *Context) ModuleCandidates(module string) []string {
+	modulePath := strings.ReplaceAll(module, ".", "/")
+	seen := make(map[string]bool)
+	candidates := []string{}
+
+	templates := append(append([]string{}, c.SearchPaths...), c.NativeSearchPaths...)
+	for _, template := range templates {
+		candidate := CanonicalModule(strings.ReplaceAll(template, "?", modulePath))
+		if candidate == "" || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates
+}
+
+func mergeTemplates(first, second []string) []string {
+	seen := make(map[string]bool)
+	merged := []string{}
+
+	for _, list := range [][]string{first, second} {
+		for _, template := range list {
+			if seen[template] {
+				continue
+			}
+			seen[template] = true
+			merged = append(merged, template)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,54 @@
+package parser
+
+import "testing"
+
+func TestCanonicalModule(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"foo.lua", "foo"},
+		{"lib/foo.lua", "foo"},
+		{"lib/foo/bar.lua", "foo.bar"},
+		{"lib/foo/init.lua", "foo"},
+		{"foo/init.lua", "foo"},
+		{"init.lua", ""},
+		{"lib/init.lua", ""},
+		{"lib/foo.luac", "foo"},
+		{"lib/foo.dll", "foo"},
+		{"lib\\foo\\bar.lua", "foo.bar"},
+		{"./lib/foo.lua", "foo"},
+		{"library/foo.lua", "library.foo"},
+		{"libfoo.lua", "libfoo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := CanonicalModule(tt.file); got != tt.want {
+				t.Errorf("CanonicalModule(%q) = %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModuleNameCustomTemplates(t *testing.T) {
+	templates := []string{"modules/?.lua", "?.lua"}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"modules/net/http.lua", "net.http"},
+		{"lib/foo.lua", "lib.foo"},
+		{"foo.lua", "foo"},
+		{"lib/foo.so", "lib.foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := ModuleName(tt.file, templates); got != tt.want {
+				t.Errorf("ModuleName(%q) = %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,786 @@
+package parser
+
+import "fmt"
+
+type luaParser struct {
+	tokens []Token
+	pos    int
+}
+
+var binaryPriority = map[string][2]int{
+	"or":  {1, 1},
+	"and": {2, 2},
+	"<":   {3, 3},
+	">":   {3, 3},
+	"<=":  {3, 3},
+	">=":  {3, 3},
+	"~=":  {3, 3},
+	"==":  {3, 3},
+	"|":   {4, 4},
+	"~":   {5, 5},
+	"&":   {6, 6},
+	"<<":  {7, 7},
+	">>":  {7, 7},
+	"..":  {9, 8},
+	"+":   {10, 10},
+	"-":   {10, 10},
+	"*":   {11, 11},
+	"/":   {11, 11},
+	"//":  {11, 11},
+	"%":   {11, 11},
+	"^":   {14, 13},
+}
+
+const unaryPriority = 12
+
+func Parse(source string) (*Chunk, error) {
+	tokens, err := Tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &luaParser{tokens: tokens}
+	block, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.Type != TokenEOF {
+		return nil, p.errorf(tok, "'<eof>' expected near %s", describe(tok))
+	}
+
+	return &Chunk{Block: block}, nil
+}
+
+func (p *luaParser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *luaParser) lookahead() Token {
+	if p.pos+1 < len(p.tokens) {
+		return p.tokens[p.pos+1]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *luaParser) next() Token {
+	tok := p.tokens[p.pos]
+	if tok.Type != TokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *luaParser) is(value string) bool {
+	tok := p.peek()
+	return (tok.Type == TokenKeyword || tok.Type == TokenSymbol) && tok.Value == value
+}
+
+func (p *luaParser) accept(value string) bool {
+	if p.is(value) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *luaParser) expect(value string) (Token, error) {
+	tok := p.peek()
+	if !p.is(value) {
+		return tok, p.errorf(tok, "'%s' expected near %s", value, describe(tok))
+	}
+	return p.next(), nil
+}
+
+func (p *luaParser) expectName() (Token, error) {
+	tok := p.peek()
+	if tok.Type != TokenName {
+		return tok, p.errorf(tok, "<name> expected near %s", describe(tok))
+	}
+	return p.next(), nil
+}
+
+func (p *luaParser) errorf(tok Token, format string, args ...interface{}) error {
+	return &SyntaxError{Line: tok.Line, Column: tok.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+func describe(tok Token) string {
+	switch tok.Type {
+	case TokenEOF:
+		return "<eof>"
+	case TokenString:
+		return "<string>"
+	default:
+		return "'" + tok.Value + "'"
+	}
+}
+
+func position(tok Token) Position {
+	return Position{Line: tok.Line, Column: tok.Column}
+}
+
+func (p *luaParser) blockFollow() bool {
+	tok := p.peek()
+	if tok.Type == TokenEOF {
+		return true
+	}
+	if tok.Type != TokenKeyword {
+		return false
+	}
+	switch tok.Value {
+	case "else", "elseif", "end", "until":
+		return true
+	}
+	return false
+}
+
+func (p *luaParser) block() (*Block, error) {
+	block := &Block{Position: position(p.peek())}
+
+	for !p.blockFollow() {
+		if p.is("return") {
+			stmt, err := p.returnStmt()
+			if err != nil {
+				return nil, err
+			}
+			block.Stmts = append(block.Stmts, stmt)
+			break
+		}
+
+		stmt, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		if stmt != nil {
+			block.Stmts = append(block.Stmts, stmt)
+		}
+	}
+
+	return block, nil
+}
+
+func (p *luaParser) returnStmt() (Stmt, error) {
+	tok := p.next()
+	stmt := &ReturnStmt{Position: position(tok)}
+
+	if !p.blockFollow() && !p.is(";") {
+		values, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Values = values
+	}
+	p.accept(";")
+
+	return stmt, nil
+}
+
+func (p *luaParser) statement() (Stmt, error) {
+	tok := p.peek()
+	pos := position(tok)
+
+	if tok.Type == TokenSymbol {
+		switch tok.Value {
+		case ";":
+			p.next()
+			return nil, nil
+		case "::":
+			p.next()
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("::"); err != nil {
+				return nil, err
+			}
+			return &LabelStmt{Position: pos, Name: name.Value}, nil
+		}
+	}
+
+	if tok.Type == TokenKeyword {
+		switch tok.Value {
+		case "if":
+			return p.ifStmt()
+		case "while":
+			p.next()
+			cond, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("do"); err != nil {
+				return nil, err
+			}
+			body, err := p.blockUntil("end")
+			if err != nil {
+				return nil, err
+			}
+			return &WhileStmt{Position: pos, Cond: cond, Body: body}, nil
+		case "do":
+			p.next()
+			body, err := p.blockUntil("end")
+			if err != nil {
+				return nil, err
+			}
+			return &DoStmt{Position: pos, Body: body}, nil
+		case "for":
+			return p.forStmt()
+		case "repeat":
+			p.next()
+			body, err := p.blockUntil("until")
+			if err != nil {
+				return nil, err
+			}
+			cond, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			return &RepeatStmt{Position: pos, Body: body, Cond: cond}, nil
+		case "function":
+			return p.functionStmt()
+		case "local":
+			p.next()
+			if p.accept("function") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				fn, err := p.functionBody(pos)
+				if err != nil {
+					return nil, err
+				}
+				return &LocalFunctionStmt{Position: pos, Name: name.Value, Func: fn}, nil
+			}
+			return p.localStmt(pos)
+		case "break":
+			p.next()
+			return &BreakStmt{Position: pos}, nil
+		case "goto":
+			if p.lookahead().Type == TokenName {
+				p.next()
+				label := p.next()
+				return &GotoStmt{Position: pos, Label: label.Value}, nil
+			}
+		}
+	}
+
+	return p.exprStmt()
+}
+
+func (p *luaParser) blockUntil(terminator string) (*Block, error) {
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(terminator); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (p *luaParser) ifStmt() (Stmt, error) {
+	stmt := &IfStmt{Position: position(p.next())}
+
+	for {
+		cond, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect("then"); err != nil {
+			return nil, err
+		}
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Clauses = append(stmt.Clauses, &IfClause{Cond: cond, Body: body})
+
+		if !p.accept("elseif") {
+			break
+		}
+	}
+
+	if p.accept("else") {
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Else = body
+	}
+
+	if _, err := p.expect("end"); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *luaParser) forStmt() (Stmt, error) {
+	pos := position(p.next())
+
+	first, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept("=") {
+		stmt := &NumericForStmt{Position: pos, Var: first.Value}
+		if stmt.Start, err = p.expr(); err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(","); err != nil {
+			return nil, err
+		}
+		if stmt.Limit, err = p.expr(); err != nil {
+			return nil, err
+		}
+		if p.accept(",") {
+			if stmt.Step, err = p.expr(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := p.expect("do"); err != nil {
+			return nil, err
+		}
+		if stmt.Body, err = p.blockUntil("end"); err != nil {
+			return nil, err
+		}
+		return stmt, nil
+	}
+
+	stmt := &GenericForStmt{Position: pos, Names: []string{first.Value}}
+	for p.accept(",") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Names = append(stmt.Names, name.Value)
+	}
+	if _, err := p.expect("in"); err != nil {
+		return nil, err
+	}
+	if stmt.Exprs, err = p.exprList(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect("do"); err != nil {
+		return nil, err
+	}
+	if stmt.Body, err = p.blockUntil("end"); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func (p *luaParser) functionStmt() (Stmt, error) {
+	pos := position(p.next())
+
+	nameTok, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	var name Expr = &NameExpr{Position: position(nameTok), Name: nameTok.Value}
+	isMethod := false
+
+	for p.is(".") || p.is(":") {
+		sep := p.next()
+		key, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		name = &IndexExpr{
+			Position: position(key),
+			Object:   name,
+			Key:      &StringExpr{Position: position(key), Value: key.Value},
+		}
+		if sep.Value == ":" {
+			isMethod = true
+			break
+		}
+	}
+
+	fn, err := p.functionBody(pos)
+	if err != nil {
+		return nil, err
+	}
+	if isMethod {
+		fn.Params = append([]string{"self"}, fn.Params...)
+	}
+
+	return &FunctionStmt{Position: pos, Name: name, IsMethod: isMethod, Func: fn}, nil
+}
+
+func (p *luaParser) localStmt(pos Position) (Stmt, error) {
+	stmt := &LocalStmt{Position: pos}
+
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Names = append(stmt.Names, name.Value)
+
+		attrib := ""
+		if p.accept("<") {
+			tok, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(">"); err != nil {
+				return nil, err
+			}
+			attrib = tok.Value
+		}
+		stmt.Attribs = append(stmt.Attribs, attrib)
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if p.accept("=") {
+		values, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Values = values
+	}
+
+	return stmt, nil
+}
+
+func (p *luaParser) exprStmt() (Stmt, error) {
+	tok := p.peek()
+	pos := position(tok)
+
+	target, err := p.suffixedExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.is("=") || p.is(",") {
+		targets := []Expr{target}
+		for p.accept(",") {
+			t, err := p.suffixedExpr()
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, t)
+		}
+
+		for _, t := range targets {
+			switch t.(type) {
+			case *NameExpr, *IndexExpr:
+			default:
+				return nil, p.errorf(tok, "syntax error near %s", describe(p.peek()))
+			}
+		}
+
+		if _, err := p.expect("="); err != nil {
+			return nil, err
+		}
+		values, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+		return &AssignStmt{Position: pos, Targets: targets, Values: values}, nil
+	}
+
+	switch target.(type) {
+	case *CallExpr, *MethodCallExpr:
+		return &CallStmt{Position: pos, Call: target}, nil
+	}
+
+	return nil, p.errorf(p.peek(), "syntax error near %s", describe(p.peek()))
+}
+
+func (p *luaParser) exprList() ([]Expr, error) {
+	first, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{first}
+	for p.accept(",") {
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+
+	return exprs, nil
+}
+
+func (p *luaParser) expr() (Expr, error) {
+	return p.subExpr(0)
+}
+
+func (p *luaParser) subExpr(limit int) (Expr, error) {
+	var left Expr
+	tok := p.peek()
+
+	if (tok.Type == TokenKeyword && tok.Value == "not") ||
+		(tok.Type == TokenSymbol && (tok.Value == "-" || tok.Value == "#" || tok.Value == "~")) {
+		p.next()
+		operand, err := p.subExpr(unaryPriority)
+		if err != nil {
+			return nil, err
+		}
+		left = &UnaryExpr{Position: position(tok), Op: tok.Value, Operand: operand}
+	} else {
+		var err error
+		if left, err = p.simpleExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		op := p.peek()
+		if op.Type != TokenSymbol && op.Type != TokenKeyword {
+			break
+		}
+		prio, ok := binaryPriority[op.Value]
+		if !ok || prio[0] <= limit {
+			break
+		}
+
+		p.next()
+		right, err := p.subExpr(prio[1])
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Position: position(op), Op: op.Value, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *luaParser) simpleExpr() (Expr, error) {
+	tok := p.peek()
+	pos := position(tok)
+
+	switch tok.Type {
+	case TokenNumber:
+		p.next()
+		return &NumberExpr{Position: pos, Value: tok.Value}, nil
+	case TokenString:
+		p.next()
+		return &StringExpr{Position: pos, Value: tok.Value, Long: tok.Long}, nil
+	case TokenKeyword:
+		switch tok.Value {
+		case "nil":
+			p.next()
+			return &NilExpr{Position: pos}, nil
+		case "true":
+			p.next()
+			return &TrueExpr{Position: pos}, nil
+		case "false":
+			p.next()
+			return &FalseExpr{Position: pos}, nil
+		case "function":
+			p.next()
+			return p.functionBody(pos)
+		}
+	case TokenSymbol:
+		switch tok.Value {
+		case "...":
+			p.next()
+			return &VarargExpr{Position: pos}, nil
+		case "{":
+			return p.tableConstructor()
+		}
+	}
+
+	return p.suffixedExpr()
+}
+
+func (p *luaParser) primaryExpr() (Expr, error) {
+	tok := p.peek()
+
+	if tok.Type == TokenName {
+		p.next()
+		return &NameExpr{Position: position(tok), Name: tok.Value}, nil
+	}
+
+	if p.accept("(") {
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &ParenExpr{Position: position(tok), Inner: inner}, nil
+	}
+
+	return nil, p.errorf(tok, "unexpected symbol near %s", describe(tok))
+}
+
+func (p *luaParser) suffixedExpr() (Expr, error) {
+	expr, err := p.primaryExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+
+		switch {
+		case p.is("."):
+			p.next()
+			key, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			expr = &IndexExpr{
+				Position: position(key),
+				Object:   expr,
+				Key:      &StringExpr{Position: position(key), Value: key.Value},
+			}
+		case p.is("["):
+			p.next()
+			key, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			expr = &IndexExpr{Position: position(tok), Object: expr, Key: key}
+		case p.is(":"):
+			p.next()
+			method, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			args, err := p.callArgs()
+			if err != nil {
+				return nil, err
+			}
+			expr = &MethodCallExpr{Position: position(method), Object: expr, Method: method.Value, Args: args}
+		case p.is("(") || p.is("{") || tok.Type == TokenString:
+			args, err := p.callArgs()
+			if err != nil {
+				return nil, err
+			}
+			expr = &CallExpr{Position: expr.Pos(), Func: expr, Args: args}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *luaParser) callArgs() ([]Expr, error) {
+	tok := p.peek()
+
+	switch {
+	case tok.Type == TokenString:
+		p.next()
+		return []Expr{&StringExpr{Position: position(tok), Value: tok.Value, Long: tok.Long}}, nil
+	case p.is("{"):
+		table, err := p.tableConstructor()
+		if err != nil {
+			return nil, err
+		}
+		return []Expr{table}, nil
+	case p.is("("):
+		p.next()
+		if p.accept(")") {
+			return []Expr{}, nil
+		}
+		args, err := p.exprList()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return args, nil
+	}
+
+	return nil, p.errorf(tok, "function arguments expected near %s", describe(tok))
+}
+
+func (p *luaParser) functionBody(pos Position) (*FunctionExpr, error) {
+	fn := &FunctionExpr{Position: pos}
+
+	if _, err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	if !p.is(")") {
+		for {
+			if p.accept("...") {
+				fn.IsVararg = true
+				break
+			}
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			fn.Params = append(fn.Params, name.Value)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if _, err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	body, err := p.blockUntil("end")
+	if err != nil {
+		return nil, err
+	}
+	fn.Body = body
+
+	return fn, nil
+}
+
+func (p *luaParser) tableConstructor() (Expr, error) {
+	open, err := p.expect("{")
+	if err != nil {
+		return nil, err
+	}
+
+	table := &TableExpr{Position: position(open), Fields: []*TableField{}}
+
+	for !p.is("}") {
+		field := &TableField{}
+
+		switch {
+		case p.is("["):
+			p.next()
+			if field.Key, err = p.expr(); err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("="); err != nil {
+				return nil, err
+			}
+		case p.peek().Type == TokenName && p.lookahead().Type == TokenSymbol && p.lookahead().Value == "=":
+			name := p.next()
+			p.next()
+			field.Key = &StringExpr{Position: position(name), Value: name.Value}
+		}
+
+		if field.Value, err = p.expr(); err != nil {
+			return nil, err
+		}
+		table.Fields = append(table.Fields, field)
+
+		if !p.accept(",") && !p.accept(";") {
+			break
+		}
+	}
+
+	if _, err := p.expect("}"); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
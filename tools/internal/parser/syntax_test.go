@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSourceRequires(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []Require
+	}{
+		{
+			name:   "call with parentheses",
+			source: `local a = require("mimgui")`,
+			want:   []Require{{Module: "mimgui", Line: 1, Column: 19}},
+		},
+		{
+			name:   "string call",
+			source: `local a = require"cjson"`,
+			want:   []Require{{Module: "cjson", Line: 1, Column: 18}},
+		},
+		{
+			name:   "long string call",
+			source: "local a = require [[lfs]]",
+			want:   []Require{{Module: "lfs", Line: 1, Column: 19}},
+		},
+		{
+			name:   "line comment",
+			source: "-- require('commented')\nlocal a = require('real')",
+			want:   []Require{{Module: "real", Line: 2, Column: 19}},
+		},
+		{
+			name:   "block comment",
+			source: "--[[\nrequire('commented')\n]]\nlocal a = require('real')",
+			want:   []Require{{Module: "real", Line: 4, Column: 19}},
+		},
+		{
+			name:   "string literal",
+			source: `local s = "require('quoted')"` + "\nlocal a = require('real')",
+			want:   []Require{{Module: "real", Line: 2, Column: 19}},
+		},
+		{
+			name:   "local constant",
+			source: "local name = 'vkeys'\nlocal a = require(name)",
+			want:   []Require{{Module: "vkeys", Inferred: true, Line: 2, Column: 19}},
+		},
+		{
+			name:   "pcall guarded",
+			source: `local ok, lfs = pcall(require, "lfs")`,
+			want:   []Require{{Module: "lfs", Optional: true, Line: 1, Column: 32}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseSource(tt.source)
+			if err != nil {
+				t.Fatalf("ParseSource: %v", err)
+			}
+			if !reflect.DeepEqual(result.Requires, tt.want) {
+				t.Errorf("requires = %+v, want %+v", result.Requires, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		line   int
+		column int
+	}{
+		{"missing then", "if x\nprint(1)\nend", 2, 1},
+		{"unfinished string", "local s = 'abc\nlocal t = 1", 1, 11},
+		{"unexpected symbol", "local = 1", 1, 7},
+		{"missing end", "function f()\nreturn 1\n", 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			syntaxErr, ok := err.(*SyntaxError)
+			if !ok {
+				t.Fatalf("Parse error = %v, want *SyntaxError", err)
+			}
+			if syntaxErr.Line != tt.line || syntaxErr.Column != tt.column {
+				t.Errorf("error at %d:%d, want %d:%d (%s)", syntaxErr.Line, syntaxErr.Column, tt.line, tt.column, syntaxErr.Message)
+			}
+		})
+	}
+}
+
+func TestParseFileFallsBackToRegex(t *testing.T) {
+	source := "local a = require('cjson')\nlocal b = require(name)\nif then\n"
+
+	result, chunk := parseFile(source)
+	if chunk != nil {
+		t.Errorf("chunk = %v, want nil for a source that does not parse", chunk)
+	}
+
+	wantRequires := []Require{{Module: "cjson", Line: 1, Column: 20}}
+	if !reflect.DeepEqual(result.Requires, wantRequires) {
+		t.Errorf("requires = %+v, want %+v", result.Requires, wantRequires)
+	}
+
+	types := []WarningType{}
+	lines := []int{}
+	for _, w := range result.Warnings {
+		types = append(types, w.Type)
+		lines = append(lines, w.Line)
+	}
+	if want := []WarningType{WarningVariableRequire, WarningParseError}; !reflect.DeepEqual(types, want) {
+		t.Errorf("warning types = %v, want %v", types, want)
+	}
+	if want := []int{2, 3}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("warning lines = %v, want %v", lines, want)
+	}
+}
@@ -0,0 +1,556 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	DefaultTraceTimeout      = 2 * time.Second
+	DefaultTraceInstructions = 5000000
+	DefaultTraceMemory       = 64 << 20
+)
+
+var (
+	errInstructionBudget = errors.New("instruction budget exhausted")
+	errMemoryBudget      = errors.New("memory budget exhausted")
+)
+
+type TraceOptions struct {
+	Timeout      time.Duration
+	Instructions int64
+	Memory       int64
+}
+
+type TraceResult struct {
+	Files    map[string]*SourceResult
+	Warnings []Warning
+}
+
+var socketCalls = []string{"tcp", "udp", "connect", "bind"}
+
+var httpModules = map[string]bool{
+	"socket.http": true,
+	"ssl.https":   true,
+}
+
+type budgetContext struct {
+	context.Context
+	remaining int64
+	done      chan struct{}
+	err       error
+}
+
+func (c *budgetContext) Done() <-chan struct{} {
+	if c.err == nil {
+		c.remaining--
+		if c.remaining < 0 {
+			c.err = errInstructionBudget
+			close(c.done)
+		}
+	}
+	if c.err != nil {
+		return c.done
+	}
+	return c.Context.Done()
+}
+
+func (c *budgetContext) exhaust(err error) {
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
+
+func (c *budgetContext) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.Context.Err()
+}
+
+type tracer struct {
+	ctx      *Context
+	state    *lua.LState
+	budget   *budgetContext
+	memory   int64
+	entry    string
+	files    map[string]bool
+	results  map[string]*SourceResult
+	loaded   map[string]lua.LValue
+	stubMeta *lua.LTable
+}
+
+func Trace(ctx *Context, entries []string, opts *TraceOptions) *TraceResult {
+	trace := &TraceResult{
+		Files:    make(map[string]*SourceResult),
+		Warnings: []Warning{},
+	}
+
+	for _, entry := range entries {
+		if err := traceEntry(ctx, entry, opts, trace.Files); err != nil {
+			trace.Warnings = append(trace.Warnings, Warning{
+				Type:     WarningTraceIncomplete,
+				File:     entry,
+				Severity: SeverityInfo,
+				Message:  "Dynamic trace stopped early: " + err.Error(),
+			})
+		}
+	}
+
+	return trace
+}
+
+func traceEntry(ctx *Context, entry string, opts *TraceOptions, results map[string]*SourceResult) (err error) {
+	content, err := os.ReadFile(filepath.Join(ctx.PackagePath, entry))
+	if err != nil {
+		return err
+	}
+	if IsBytecode(string(content)) {
+		return fmt.Errorf("precompiled bytecode cannot be traced")
+	}
+
+	deadline, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	L := lua.NewState(lua.Options{
+		SkipOpenLibs:    true,
+		CallStackSize:   256,
+		RegistrySize:    1024 * 16,
+		RegistryMaxSize: 1024 * 256,
+	})
+	defer L.Close()
+	budget := &budgetContext{Context: deadline, remaining: opts.Instructions, done: make(chan struct{})}
+	L.SetContext(budget)
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	t := &tracer{
+		ctx:     ctx,
+		state:   L,
+		budget:  budget,
+		memory:  opts.Memory,
+		entry:   entry,
+		files:   map[string]bool{entry: true},
+		results: results,
+		loaded:  make(map[string]lua.LValue),
+	}
+	for _, file := range ctx.ModuleFiles {
+		t.files[file] = true
+	}
+	t.openLibs()
+
+	fn, err := L.Load(bytes.NewReader(content), entry)
+	if err != nil {
+		return err
+	}
+	L.Push(fn)
+	if err := L.PCall(0, 0, nil); err != nil {
+		return traceError(err, budget)
+	}
+
+	// main usually loops until the game closes, so running out of time or
+	// instructions there is expected and not reported.
+	if main, ok := L.GetGlobal("main").(*lua.LFunction); ok {
+		L.Push(main)
+		if err := L.PCall(0, 0, nil); err != nil && (budget.Err() == nil || errors.Is(budget.Err(), errMemoryBudget)) {
+			return traceError(err, budget)
+		}
+	}
+
+	return nil
+}
+
+func traceError(err error, budget *budgetContext) error {
+	if errors.Is(budget.Err(), errMemoryBudget) {
+		return errMemoryBudget
+	}
+	var apiErr *lua.ApiError
+	if errors.As(err, &apiErr) {
+		return errors.New(apiErr.Object.String())
+	}
+	return err
+}
+
+func (t *tracer) openLibs() {
+	L := t.state
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	t.limitAllocations()
+
+	t.stubMeta = L.NewTable()
+	stub := L.NewFunction(func(L *lua.LState) int {
+		L.Push(t.stub())
+		return 1
+	})
+	zero := L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LNumber(0))
+		return 1
+	})
+	for _, event := range []string{"__add", "__sub", "__mul", "__div", "__mod", "__pow", "__unm", "__len"} {
+		t.stubMeta.RawSetString(event, zero)
+	}
+	t.stubMeta.RawSetString("__index", stub)
+	t.stubMeta.RawSetString("__call", stub)
+	t.stubMeta.RawSetString("__concat", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LString(stubString(L.Get(1)) + stubString(L.Get(2))))
+		return 1
+	}))
+	t.stubMeta.RawSetString("__lt", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LFalse)
+		return 1
+	}))
+	t.stubMeta.RawSetString("__le", t.stubMeta.RawGetString("__lt"))
+
+	globals := L.Get(lua.GlobalsIndex).(*lua.LTable)
+	globalsMeta := L.NewTable()
+	globalsMeta.RawSetString("__index", stub)
+	L.SetMetatable(globals, globalsMeta)
+
+	L.SetGlobal("require", L.NewFunction(t.require))
+	L.SetGlobal("dofile", L.NewFunction(t.fileAccess(nil)))
+	L.SetGlobal("loadfile", L.NewFunction(t.fileAccess(nil)))
+	L.SetGlobal("print", L.NewFunction(func(L *lua.LState) int { return 0 }))
+	L.SetGlobal("wait", L.NewFunction(func(L *lua.LState) int { return 0 }))
+	L.SetGlobal("downloadUrlToFile", L.NewFunction(t.capability(CapabilityDownload, "downloadUrlToFile")))
+
+	pkg := t.stub()
+	pkg.RawSetString("path", lua.LString(""))
+	pkg.RawSetString("cpath", lua.LString(""))
+	pkg.RawSetString("loaded", L.NewTable())
+	pkg.RawSetString("preload", L.NewTable())
+	pkg.RawSetString("loadlib", L.NewFunction(t.nativeLoad("package.loadlib")))
+	L.SetGlobal("package", pkg)
+
+	io := t.stub()
+	io.RawSetString("open", L.NewFunction(t.fileAccess(lua.LString("sandboxed"))))
+	io.RawSetString("lines", L.NewFunction(t.fileAccess(nil)))
+	io.RawSetString("popen", L.NewFunction(t.capability(CapabilityProcess, "io.popen")))
+	L.SetGlobal("io", io)
+
+	osTable := t.stub()
+	osTable.RawSetString("time", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LNumber(time.Now().Unix()))
+		return 1
+	}))
+	osTable.RawSetString("clock", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LNumber(0))
+		return 1
+	}))
+	osTable.RawSetString("date", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LString(""))
+		return 1
+	}))
+	osTable.RawSetString("getenv", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LNil)
+		return 1
+	}))
+	osTable.RawSetString("execute", L.NewFunction(t.capability(CapabilityProcess, "os.execute")))
+	osTable.RawSetString("remove", L.NewFunction(t.fileAccess(lua.LString("sandboxed"))))
+	osTable.RawSetString("rename", L.NewFunction(t.fileAccess(lua.LString("sandboxed"))))
+	osTable.RawSetString("exit", L.NewFunction(func(L *lua.LState) int {
+		L.RaiseError("os.exit called")
+		return 0
+	}))
+	L.SetGlobal("os", osTable)
+}
+
+// limitAllocations wraps the library functions that can build a large string
+// in a single call, so that a package cannot exhaust the validator's memory.
+func (t *tracer) limitAllocations() {
+	L := t.state
+
+	str := L.GetGlobal(lua.StringLibName).(*lua.LTable)
+	rep := str.RawGetString("rep").(*lua.LFunction).GFunction
+	str.RawSetString("rep", L.NewFunction(func(L *lua.LState) int {
+		size := int64(len(L.CheckString(1)))
+		if count := int64(L.CheckInt(2)); count > 0 && size > 0 {
+			if count > t.memory/size {
+				t.exhaustMemory()
+			}
+			t.allocate(size * count)
+		}
+		return rep(L)
+	}))
+
+	tbl := L.GetGlobal(lua.TabLibName).(*lua.LTable)
+	concat := tbl.RawGetString("concat").(*lua.LFunction).GFunction
+	tbl.RawSetString("concat", L.NewFunction(func(L *lua.LState) int {
+		items := L.CheckTable(1)
+		size := int64(len(L.OptString(2, ""))) * int64(items.Len())
+		for i := 1; i <= items.Len(); i++ {
+			switch value := items.RawGetInt(i).(type) {
+			case lua.LString:
+				size += int64(len(value))
+			case lua.LNumber:
+				size += int64(len(value.String()))
+			}
+		}
+		t.allocate(size)
+		return concat(L)
+	}))
+}
+
+func (t *tracer) allocate(size int64) {
+	if size > t.memory {
+		t.exhaustMemory()
+	}
+	t.memory -= size
+}
+
+func (t *tracer) exhaustMemory() {
+	t.budget.exhaust(errMemoryBudget)
+	t.state.RaiseError("%s", errMemoryBudget.Error())
+}
+
+func stubString(value lua.LValue) string {
+	switch value.(type) {
+	case lua.LString, lua.LNumber:
+		return value.String()
+	}
+	return ""
+}
+
+func (t *tracer) stub() *lua.LTable {
+	table := t.state.NewTable()
+	t.state.SetMetatable(table, t.stubMeta)
+	return table
+}
+
+func (t *tracer) caller() (*SourceResult, Position, bool) {
+	L := t.state
+	viaBuiltin := false
+	for level := 1; ; level++ {
+		dbg, ok := L.GetStack(level)
+		if !ok {
+			break
+		}
+		if _, err := L.GetInfo("Sl", dbg, lua.LNil); err != nil {
+			break
+		}
+		if dbg.What == "G" {
+			if level == 1 {
+				viaBuiltin = true
+			}
+			continue
+		}
+		if t.files[dbg.Source] {
+			return t.result(dbg.Source), Position{Line: dbg.CurrentLine}, viaBuiltin
+		}
+	}
+	return t.result(t.entry), Position{}, viaBuiltin
+}
+
+func (t *tracer) result(file string) *SourceResult {
+	if result, ok := t.results[file]; ok {
+		return result
+	}
+	result := &SourceResult{
+		RawModules:      []string{},
+		OptionalModules: []string{},
+		Requires:        []Require{},
+		NativeLibraries: []NativeLibrary{},
+		Capabilities:    []Capability{},
+		FilePaths:       []string{},
+	}
+	t.results[file] = result
+	return result
+}
+
+func (t *tracer) require(L *lua.LState) int {
+	module := L.CheckString(1)
+	result, pos, optional := t.caller()
+	result.addRequire(Require{Module: module, Optional: optional, Dynamic: true}, pos)
+
+	value, ok := t.loaded[module]
+	if !ok {
+		value = t.load(module)
+		t.loaded[module] = value
+	}
+	L.Push(value)
+	return 1
+}
+
+func (t *tracer) load(module string) lua.LValue {
+	L := t.state
+
+	switch {
+	case module == "ffi":
+		ffi := t.stub()
+		ffi.RawSetString("cdef", L.NewFunction(func(L *lua.LState) int { return 0 }))
+		ffi.RawSetString("load", L.NewFunction(t.nativeLoad("ffi.load")))
+		return ffi
+	case module == "socket":
+		socket := t.stub()
+		for _, call := range socketCalls {
+			socket.RawSetString(call, L.NewFunction(t.capability(CapabilityNetwork, "socket."+call)))
+		}
+		return socket
+	case httpModules[module]:
+		http := t.stub()
+		http.RawSetString("request", L.NewFunction(t.capability(CapabilityNetwork, module+".request")))
+		return http
+	}
+
+	file := t.ctx.ModuleFile(module)
+	if file == "" || isNativeFile(file) {
+		return t.stub()
+	}
+	content, err := os.ReadFile(filepath.Join(t.ctx.PackagePath, file))
+	if err != nil || IsBytecode(string(content)) {
+		return t.stub()
+	}
+
+	fn, err := L.Load(bytes.NewReader(content), file)
+	if err != nil {
+		L.RaiseError("%s", err.Error())
+	}
+	L.Push(fn)
+	L.Push(lua.LString(module))
+	L.Call(1, 1)
+	value := L.Get(-1)
+	L.Pop(1)
+	if value == lua.LNil {
+		return lua.LTrue
+	}
+	return value
+}
+
+func (t *tracer) capability(kind CapabilityKind, detail string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		result, pos, _ := t.caller()
+		result.addCapability(kind, detail, pos)
+		L.Push(t.stub())
+		return 1
+	}
+}
+
+func (t *tracer) fileAccess(failure lua.LValue) lua.LGFunction {
+	return func(L *lua.LState) int {
+		path := L.OptString(1, "")
+		result, pos, _ := t.caller()
+		if path != "" {
+			result.FilePaths = append(result.FilePaths, path)
+			result.addCapability(CapabilityFileAccess, path, pos)
+		}
+		if failure == nil {
+			L.Push(t.stub())
+			return 1
+		}
+		L.Push(lua.LNil)
+		L.Push(failure)
+		return 2
+	}
+}
+
+func (t *tracer) nativeLoad(loader string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		name := L.CheckString(1)
+		result, pos, optional := t.caller()
+		result.NativeLibraries = append(result.NativeLibraries, NativeLibrary{
+			Name:     LibraryName(name),
+			Loader:   loader,
+			Optional: optional,
+			Dynamic:  true,
+			Line:     pos.Line,
+		})
+		L.Push(t.stub())
+		return 1
+	}
+}
+
+func (a *Analysis) mergeTrace(trace *TraceResult) (raw, optional []string) {
+	requires := make(map[string]bool)
+	for _, req := range a.Requires {
+		requires[req.File+"\x00"+req.Module] = true
+	}
+	libraries := make(map[string]bool)
+	for _, lib := range a.NativeLibraries {
+		libraries[lib.File+"\x00"+lib.Name] = true
+	}
+	capabilities := make(map[string]bool)
+	for _, capability := range a.Capabilities {
+		capabilities[capability.File+"\x00"+string(capability.Kind)+"\x00"+capability.Detail] = true
+	}
+
+	files := make([]string, 0, len(trace.Files))
+	for file := range trace.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		result := trace.Files[file]
+
+		for _, req := range result.Requires {
+			key := file + "\x00" + req.Module
+			if requires[key] {
+				continue
+			}
+			requires[key] = true
+			req.File = file
+			a.Requires = append(a.Requires, req)
+			if req.Optional {
+				optional = append(optional, req.Module)
+			} else {
+				raw = append(raw, req.Module)
+			}
+		}
+
+		for _, lib := range result.NativeLibraries {
+			key := file + "\x00" + lib.Name
+			if libraries[key] {
+				continue
+			}
+			libraries[key] = true
+			lib.File = file
+			a.NativeLibraries = append(a.NativeLibraries, lib)
+		}
+
+		for _, capability := range result.Capabilities {
+			key := file + "\x00" + string(capability.Kind) + "\x00" + capability.Detail
+			if capabilities[key] {
+				continue
+			}
+			capabilities[key] = true
+			capability.File = file
+			capability.Dynamic = true
+			a.Capabilities = append(a.Capabilities, capability)
+		}
+
+		a.FilePaths = append(a.FilePaths, result.FilePaths...)
+		if result.UsesNetwork {
+			a.UsesNetwork = true
+		}
+		if result.UsesFFI {
+			a.UsesFFI = true
+		}
+	}
+
+	a.Warnings = append(a.Warnings, trace.Warnings...)
+
+	return raw, optional
+}
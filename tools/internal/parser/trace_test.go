@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceBudgets(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		opts   TraceOptions
+		want   string
+	}{
+		{
+			name:   "completes",
+			source: "local s = string.rep('x', 10)\nfunction main() while true do wait(0) end end",
+			opts:   TraceOptions{Timeout: time.Second, Instructions: 100000, Memory: 1024},
+		},
+		{
+			name:   "instruction budget",
+			source: "while true do end",
+			opts:   TraceOptions{Timeout: time.Minute, Instructions: 1000, Memory: 1024},
+			want:   "instruction budget exhausted",
+		},
+		{
+			name:   "timeout",
+			source: "while true do end",
+			opts:   TraceOptions{Timeout: 50 * time.Millisecond, Instructions: 1 << 62, Memory: 1024},
+			want:   "context deadline exceeded",
+		},
+		{
+			name:   "string.rep",
+			source: "local s = string.rep('x', 2^33)",
+			opts:   TraceOptions{Timeout: time.Second, Instructions: 100000, Memory: 1 << 20},
+			want:   "memory budget exhausted",
+		},
+		{
+			name:   "string.rep caught by pcall",
+			source: "pcall(string.rep, 'x', 2^33)\nlocal s = 'done'",
+			opts:   TraceOptions{Timeout: time.Second, Instructions: 100000, Memory: 1 << 20},
+			want:   "memory budget exhausted",
+		},
+		{
+			name:   "table.concat",
+			source: "local parts = {}\nfor i = 1, 100 do parts[i] = string.rep('x', 1000) end\nlocal s = table.concat(parts, ',')",
+			opts:   TraceOptions{Timeout: time.Second, Instructions: 100000, Memory: 150000},
+			want:   "memory budget exhausted",
+		},
+		{
+			name:   "string.rep in main",
+			source: "function main() local s = string.rep('x', 2^33) end",
+			opts:   TraceOptions{Timeout: time.Second, Instructions: 100000, Memory: 1 << 20},
+			want:   "memory budget exhausted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := packageContext(t, map[string]string{"hud.lua": tt.source})
+			trace := Trace(ctx, []string{"hud.lua"}, &tt.opts)
+
+			if tt.want == "" {
+				if len(trace.Warnings) != 0 {
+					t.Errorf("warnings = %+v, want none", trace.Warnings)
+				}
+				return
+			}
+			if len(trace.Warnings) != 1 {
+				t.Fatalf("warnings = %+v, want one", trace.Warnings)
+			}
+			w := trace.Warnings[0]
+			if w.Type != WarningTraceIncomplete || w.File != "hud.lua" || !strings.Contains(w.Message, tt.want) {
+				t.Errorf("warning = %+v, want %s in hud.lua", w, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceMergesRequires(t *testing.T) {
+	ctx := packageContext(t, map[string]string{
+		"hud.lua":      "local name = string.lower('VKEYS')\nlocal vkeys = require(name)\nrequire('hud.util')",
+		"hud/util.lua": "local ok = pcall(require, string.lower('CJSON'))",
+	})
+	ctx.Registry.AddPackage(&PackageInfo{ID: "vkeys"})
+	ctx.Registry.AddPackage(&PackageInfo{ID: "cjson"})
+	ctx.Trace = &TraceOptions{Timeout: time.Second, Instructions: 100000, Memory: 1 << 20}
+
+	analysis, err := AnalyzeWithContext(ctx, ctx.PackagePath)
+	if err != nil {
+		t.Fatalf("AnalyzeWithContext: %v", err)
+	}
+
+	if !reflect.DeepEqual(analysis.Dependencies, []string{"vkeys"}) {
+		t.Errorf("dependencies = %v, want [vkeys]", analysis.Dependencies)
+	}
+	if !reflect.DeepEqual(analysis.OptionalDependencies, []string{"cjson"}) {
+		t.Errorf("optional dependencies = %v, want [cjson]", analysis.OptionalDependencies)
+	}
+
+	traced := []Require{}
+	for _, req := range analysis.Requires {
+		if req.Dynamic && req.Module != "" {
+			traced = append(traced, Require{Module: req.Module, File: req.File, Line: req.Line, Optional: req.Optional})
+		}
+	}
+	want := []Require{
+		{Module: "vkeys", File: "hud.lua", Line: 2},
+		{Module: "cjson", File: "hud/util.lua", Line: 1, Optional: true},
+	}
+	if !reflect.DeepEqual(traced, want) {
+		t.Errorf("traced requires = %+v, want %+v", traced, want)
+	}
+}
+
+func packageContext(t *testing.T, files map[string]string) *Context {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, err := NewContext("hud", dir, NewRegistry())
+	if err != nil {
+		t.Fatalf("NewContext: %v", err)
+	}
+	return ctx
+}
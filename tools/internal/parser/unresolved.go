@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+const maxSuggestions = 3
+
+type UnresolvedDependency struct {
+	Module      string
+	PackageID   string
+	File        string
+	Line        int
+	Column      int
+	Optional    bool
+	Suggestions []string
+}
+
+func UnresolvedRequires(ctx *Context, requires []Require) []UnresolvedDependency {
+	unresolved := []UnresolvedDependency{}
+
+	for _, req := range requires {
+		pkgID := ctx.ResolvePackage(req.Module)
+		if pkgID == "" || ctx.Registry.GetPackage(pkgID) != nil {
+			continue
+		}
+
+		unresolved = append(unresolved, UnresolvedDependency{
+			Module:      req.Module,
+			PackageID:   pkgID,
+			File:        req.File,
+			Line:        req.Line,
+			Column:      req.Column,
+			Optional:    req.Optional,
+			Suggestions: ctx.Registry.Suggest(req.Module),
+		})
+	}
+
+	return unresolved
+}
+
+func (r *Registry) Suggest(module string) []string {
+	type match struct {
+		pkgID    string
+		distance int
+	}
+
+	target := normalizeModuleName(module)
+	root := normalizeModuleName(strings.Split(module, ".")[0])
+	best := make(map[string]int)
+
+	consider := func(name, pkgID string) {
+		candidate := normalizeModuleName(name)
+		if candidate == "" {
+			return
+		}
+
+		distance := editDistance(target, candidate)
+		if d := editDistance(root, candidate); d < distance {
+			distance = d
+		}
+
+		limit := len(candidate) / 3
+		if limit < 1 {
+			limit = 1
+		}
+
+		if distance > limit && !(strings.HasPrefix(target, candidate) && len(candidate) >= 4) {
+			return
+		}
+
+		if existing, ok := best[pkgID]; !ok || distance < existing {
+			best[pkgID] = distance
+		}
+	}
+
+	for id := range r.packages {
+		consider(id, id)
+	}
+	for alias, id := range r.provides {
+		consider(alias, id)
+	}
+	for module, id := range r.natives {
+		consider(module, id)
+	}
+
+	matches := []match{}
+	for pkgID, distance := range best {
+		matches = append(matches, match{pkgID, distance})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].pkgID < matches[j].pkgID
+	})
+
+	suggestions := []string{}
+	for _, m := range matches {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, m.pkgID)
+	}
+
+	return suggestions
+}
+
+func normalizeModuleName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("-", "", "_", "", ".", "", " ", "").Replace(name)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
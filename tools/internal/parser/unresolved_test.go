@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"lfs", "", 3},
+		{"", "lfs", 3},
+		{"vkeys", "vkeys", 0},
+		{"vkey", "vkeys", 1},
+		{"vkeys", "vkyes", 2},
+		{"imgui", "mimgui", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := editDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	registry := NewRegistry()
+	registry.AddPackage(&PackageInfo{ID: "vkeys"})
+	registry.AddPackage(&PackageInfo{ID: "mimgui"})
+	registry.AddPackage(&PackageInfo{ID: "imgui"})
+	registry.AddPackage(&PackageInfo{ID: "samp-events", Provides: []string{"samp.events"}})
+	registry.AddPackage(&PackageInfo{ID: "luasocket", Provides: []string{"socket"}})
+	registry.AddPackage(&PackageInfo{ID: "inicfg"})
+
+	tests := []struct {
+		module string
+		want   []string
+	}{
+		{"vkey", []string{"vkeys"}},
+		{"VKeys", []string{"vkeys"}},
+		{"imgu", []string{"imgui", "mimgui"}},
+		{"mimgu", []string{"mimgui"}},
+		{"samp_events", []string{"samp-events"}},
+		{"sockets", []string{"luasocket"}},
+		{"inicfg.extra", []string{"inicfg"}},
+		{"json", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			if got := registry.Suggest(tt.module); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %v, want %v", tt.module, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package pool
+
+import (
+	"runtime"
+	"sync"
+)
+
+func DefaultJobs() int {
+	return runtime.NumCPU()
+}
+
+func Map[T, R any](jobs int, items []T, fn func(T) R) []R {
+	results := make([]R, len(items))
+	if jobs < 1 {
+		jobs = 1
+	}
+	if jobs > len(items) {
+		jobs = len(items)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = fn(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,55 @@
+package pool
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		name  string
+		jobs  int
+		items []int
+	}{
+		{"empty", 4, []int{}},
+		{"zero jobs", 0, []int{1, 2, 3}},
+		{"one job", 1, []int{1, 2, 3, 4, 5}},
+		{"more jobs than items", 16, []int{1, 2, 3}},
+		{"several jobs", 4, []int{9, 1, 8, 2, 7, 3, 6, 4, 5, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak int32
+			got := Map(tt.jobs, tt.items, func(n int) int {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if current <= p || atomic.CompareAndSwapInt32(&peak, p, current) {
+						break
+					}
+				}
+				// Larger items finish first, so a result written out of
+				// place would show up as a wrong order.
+				time.Sleep(time.Duration(10-n) * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return n * n
+			})
+
+			want := make([]int, len(tt.items))
+			for i, n := range tt.items {
+				want[i] = n * n
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Map = %v, want %v", got, want)
+			}
+
+			limit := int32(max(tt.jobs, 1))
+			if peak > limit {
+				t.Errorf("%d items ran at once, want at most %d", peak, limit)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package registry
 
+import "sort"
+
 var WellKnownAliases = map[string][]string{
 	"luasocket": {
 		"socket",
@@ -56,8 +58,14 @@ func GetAliases(packageID string) []string {
 }
 
 func ResolveAlias(alias string) string {
-	for pkgID, aliases := range WellKnownAliases {
-		for _, a := range aliases {
+	pkgIDs := make([]string, 0, len(WellKnownAliases))
+	for pkgID := range WellKnownAliases {
+		pkgIDs = append(pkgIDs, pkgID)
+	}
+	sort.Strings(pkgIDs)
+
+	for _, pkgID := range pkgIDs {
+		for _, a := range WellKnownAliases[pkgID] {
 			if a == alias {
 				return pkgID
 			}
@@ -110,6 +110,47 @@ func (c *Client) GetLatestVersion(itemType, id string) (string, error) {
 	return pkg.Latest, nil
 }
 
+func (c *Client) GetManifest(itemType, id, version string) (*Manifest, error) {
+	index, err := c.getIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg *Package
+	switch itemType {
+	case "deps", "dependencies":
+		pkg = index.Dependencies[id]
+	case "scripts":
+		pkg = index.Scripts[id]
+	default:
+		return nil, fmt.Errorf("unknown item type: %s", itemType)
+	}
+
+	if pkg == nil {
+		return nil, fmt.Errorf("package not found: %s", id)
+	}
+
+	if version == "" {
+		version = pkg.Latest
+	}
+
+	v := pkg.Versions[version]
+	if v == nil || v.Manifest == nil {
+		return nil, fmt.Errorf("version not found: %s@%s", id, version)
+	}
+
+	return v.Manifest, nil
+}
+
+func (c *Client) GetOptionalDependencies(itemType, id, version string) (map[string]string, error) {
+	m, err := c.GetManifest(itemType, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.OptionalDependencies, nil
+}
+
 func (c *Client) CheckDuplicate(itemType, id, version string) (*DuplicateInfo, error) {
 	index, err := c.getIndex()
 	if err != nil {
@@ -181,6 +222,25 @@ func (c *Client) GetAllScripts() ([]string, error) {
 	return scripts, nil
 }
 
+func (c *Client) GetScriptsForMoonLoader(moonloader int) ([]string, error) {
+	index, err := c.getIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	scripts := []string{}
+	for id, pkg := range index.Scripts {
+		v := pkg.Versions[pkg.Latest]
+		if v == nil || v.MoonLoader > moonloader {
+			continue
+		}
+		scripts = append(scripts, id)
+	}
+	sort.Strings(scripts)
+
+	return scripts, nil
+}
+
 func (c *Client) IsAvailable() bool {
 	_, err := c.getIndex()
 	return err == nil
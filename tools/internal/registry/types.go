@@ -15,31 +15,69 @@ type Package struct {
 }
 
 type Version struct {
-	URL      string    `json:"url"`
-	SHA256   string    `json:"sha256"`
-	Size     int64     `json:"size"`
-	Manifest *Manifest `json:"manifest"`
+	URL        string            `json:"url"`
+	SHA256     string            `json:"sha256"`
+	Size       int64             `json:"size"`
+	MoonLoader int               `json:"moonloader,omitempty"`
+	Authors    []string          `json:"authors,omitempty"`
+	Install    map[string]string `json:"install,omitempty"`
+	Manifest   *Manifest         `json:"manifest"`
 }
 
 type Manifest struct {
-	ManifestVersion string            `json:"manifestVersion"`
-	ID              string            `json:"id"`
-	Version         string            `json:"version"`
-	Files           map[string]File   `json:"files"`
-	Dependencies    map[string]string `json:"dependencies,omitempty"`
-	Security        Security          `json:"security,omitempty"`
-	Metadata        Metadata          `json:"metadata,omitempty"`
+	ManifestVersion      string            `json:"manifestVersion"`
+	ID                   string            `json:"id"`
+	Version              string            `json:"version"`
+	Files                map[string]File   `json:"files"`
+	Install              *Install          `json:"install,omitempty"`
+	Dependencies         map[string]string `json:"dependencies,omitempty"`
+	OptionalDependencies map[string]string `json:"optionalDependencies,omitempty"`
+	ScriptDependencies   map[string]string `json:"scriptDependencies,omitempty"`
+	PeerDependencies     map[string]string `json:"peerDependencies,omitempty"`
+	Conflicts            map[string]string `json:"conflicts,omitempty"`
+	Replaces             map[string]string `json:"replaces,omitempty"`
+	LuaCompat            string            `json:"luaCompat,omitempty"`
+	Script               *Script           `json:"script,omitempty"`
+	Security             Security          `json:"security,omitempty"`
+	Metadata             Metadata          `json:"metadata,omitempty"`
+}
+
+type Install struct {
+	Root  string            `json:"root"`
+	Files map[string]string `json:"files,omitempty"`
 }
 
 type File struct {
-	SHA256 string `json:"sha256"`
-	Size   int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type Capability struct {
+	Kind    string `json:"kind"`
+	Detail  string `json:"detail,omitempty"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Dynamic bool   `json:"dynamic,omitempty"`
 }
 
 type Security struct {
-	NetworkAccess bool     `json:"networkAccess,omitempty"`
-	FileAccess    []string `json:"fileAccess,omitempty"`
-	UsesFFI       bool     `json:"usesFFI,omitempty"`
+	NetworkAccess bool         `json:"networkAccess,omitempty"`
+	FileAccess    []string     `json:"fileAccess,omitempty"`
+	UsesFFI       bool         `json:"usesFFI,omitempty"`
+	Capabilities  []Capability `json:"capabilities,omitempty"`
+	Precompiled   bool         `json:"precompiled,omitempty"`
+	Obfuscated    bool         `json:"obfuscated,omitempty"`
+}
+
+type Script struct {
+	Authors       []string `json:"authors,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	URL           string   `json:"url,omitempty"`
+	VersionNumber int      `json:"versionNumber,omitempty"`
+	MoonLoader    int      `json:"moonloader,omitempty"`
+	Properties    []string `json:"properties,omitempty"`
+	Dependencies  []string `json:"dependencies,omitempty"`
 }
 
 type Metadata struct {
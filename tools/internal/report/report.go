@@ -0,0 +1,115 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+	"github.com/Deps-Tech/deps-registry/tools/internal/validator"
+)
+
+const (
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+type Finding struct {
+	Package  string `json:"package"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	RuleID   string `json:"ruleId"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func FromWarning(pkg, dir string, w parser.Warning) Finding {
+	return Finding{
+		Package:  pkg,
+		File:     path.Join(dir, w.File),
+		Line:     w.Line,
+		Column:   w.Column,
+		RuleID:   w.RuleID(),
+		Severity: w.Severity.String(),
+		Message:  w.Message,
+	}
+}
+
+func FromIssue(pkg, dir string, issue *validator.Issue) Finding {
+	severity := LevelWarning
+	if issue.Fatal {
+		severity = LevelError
+	}
+
+	return Finding{
+		Package:  pkg,
+		File:     path.Join(dir, issue.File),
+		Line:     issue.Line,
+		Column:   issue.Column,
+		RuleID:   issue.Rule,
+		Severity: severity,
+		Message:  issue.Message,
+	}
+}
+
+func Sort(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.RuleID < b.RuleID
+	})
+}
+
+func Write(w io.Writer, format, version string, findings []Finding) error {
+	var doc interface{}
+	switch format {
+	case FormatJSON:
+		doc = struct {
+			Findings []Finding `json:"findings"`
+		}{findings}
+	case FormatSARIF:
+		doc = sarifLog(version, findings)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func WriteFile(file, format, version string, findings []Finding) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := Write(f, format, version, findings); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,33 @@
+package report
+
+import "testing"
+
+func TestSarifURI(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"deps/mimgui/1.0.0/init.lua", "deps/mimgui/1.0.0/init.lua"},
+		{"scripts/arizona-hud-editor/1.0.0/[ARZ] CEF HUD Editor.lua", "scripts/arizona-hud-editor/1.0.0/%5BARZ%5D%20CEF%20HUD%20Editor.lua"},
+		{"deps/sa-mp api/1.0.0/lib/samp.lua", "deps/sa-mp%20api/1.0.0/lib/samp.lua"},
+		{"deps/x/1.0.0/100%.lua", "deps/x/1.0.0/100%25.lua"},
+		{"deps/x/1.0.0/a#b?.lua", "deps/x/1.0.0/a%23b%3F.lua"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := sarifURI(tt.file); got != tt.want {
+				t.Errorf("sarifURI(%q) = %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSarifLogEncodesURIs(t *testing.T) {
+	log := sarifLog("dev", []Finding{{RuleID: "unresolved-require", File: "scripts/a b/1.0/[x].lua", Line: 3}})
+
+	got := log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI
+	if want := "scripts/a%20b/1.0/%5Bx%5D.lua"; got != want {
+		t.Errorf("uri = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+)
+
+func InspectCompat(analysis *parser.Analysis, m *manifest.Manifest) []*Issue {
+	issues := []*Issue{}
+
+	seen := make(map[string]bool)
+	for _, f := range analysis.Compat {
+		key := f.File + "\x00" + f.Feature
+		if f.Guarded || f.Level == parser.CompatLuaJIT || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if f.Syntax {
+			issues = append(issues, &Issue{
+				File:    f.File,
+				Line:    f.Line,
+				Column:  f.Column,
+				Rule:    RuleCompatSyntax,
+				Fatal:   true,
+				Message: fmt.Sprintf("%s syntax requires %s and does not parse under LuaJIT", f.Feature, f.Level),
+			})
+		} else {
+			issues = append(issues, &Issue{
+				File:    f.File,
+				Line:    f.Line,
+				Column:  f.Column,
+				Rule:    RuleCompatLibrary,
+				Message: fmt.Sprintf("%s requires %s and is missing in LuaJIT", f.Feature, f.Level),
+			})
+		}
+	}
+
+	detected := string(analysis.LuaCompat)
+	if m.LuaCompat != "" && m.LuaCompat != detected {
+		issues = append(issues, &Issue{
+			File:    "dep.json",
+			Rule:    RuleCompatOutdated,
+			Message: fmt.Sprintf("luaCompat is %s but sources require %s", m.LuaCompat, detected),
+		})
+	}
+
+	return issues
+}
@@ -46,6 +46,9 @@ func buildGraph(manifests map[string]*manifest.Manifest) map[string]*GraphNode {
 		for depID := range m.Dependencies {
 			deps = append(deps, depID)
 		}
+		for scriptID := range m.ScriptDependencies {
+			deps = append(deps, scriptID)
+		}
 		graph[id] = &GraphNode{
 			ID:           id,
 			Dependencies: deps,
@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+	"github.com/Deps-Tech/deps-registry/tools/internal/registry"
+)
+
+var runtimeDependencies = map[string]bool{
+	"cleo":       true,
+	"moonloader": true,
+	"samp":       true,
+	"sampfuncs":  true,
+}
+
+func ResolveDeclaredDependency(name string, reg *parser.Registry) string {
+	for _, candidate := range []string{name, strings.ToLower(name)} {
+		if pkgID := reg.ResolveModule(candidate); pkgID != "" {
+			return pkgID
+		}
+		if pkgID := registry.ResolveAlias(candidate); pkgID != "" {
+			return pkgID
+		}
+	}
+	return ""
+}
+
+func CheckDeclaredDependencies(m *manifest.Manifest, reg *parser.Registry) []*Issue {
+	issues := []*Issue{}
+	if m.Script == nil || len(m.Script.Dependencies) == 0 {
+		return issues
+	}
+
+	declared := make(map[string]bool)
+	for _, name := range m.Script.Dependencies {
+		if runtimeDependencies[strings.ToLower(name)] {
+			continue
+		}
+
+		pkgID := ResolveDeclaredDependency(name, reg)
+		if pkgID == "" {
+			issues = append(issues, &Issue{
+				File:    "dep.json",
+				Rule:    RuleUnknownDeclared,
+				Message: fmt.Sprintf("declared dependency %s is not in the registry", name),
+			})
+			continue
+		}
+		declared[pkgID] = true
+
+		_, required := m.Dependencies[pkgID]
+		_, optional := m.OptionalDependencies[pkgID]
+		if !required && !optional {
+			issues = append(issues, &Issue{
+				File:    "dep.json",
+				Rule:    RuleUnusedDeclared,
+				Message: fmt.Sprintf("declared dependency %s is never required", name),
+			})
+		}
+	}
+
+	// The runtime packages ship with MoonLoader and SA-MP, so requiring them
+	// (samp.events, moonloader) never needs a matching declaration.
+	undeclared := []string{}
+	for pkgID := range m.Dependencies {
+		if !declared[pkgID] && !runtimeDependencies[pkgID] {
+			undeclared = append(undeclared, pkgID)
+		}
+	}
+	sort.Strings(undeclared)
+
+	for _, pkgID := range undeclared {
+		issues = append(issues, &Issue{
+			File:    "dep.json",
+			Rule:    RuleUndeclaredDependency,
+			Message: fmt.Sprintf("required dependency %s is not declared", pkgID),
+		})
+	}
+
+	return issues
+}
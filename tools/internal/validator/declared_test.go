@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+)
+
+func TestCheckDeclaredDependencies(t *testing.T) {
+	reg := parser.NewRegistry()
+	for _, id := range []string{"mimgui", "vkeys", "samp", "sampfuncs", "moonloader"} {
+		reg.AddPackage(&parser.PackageInfo{ID: id})
+	}
+
+	tests := []struct {
+		name     string
+		declared []string
+		required []string
+		optional []string
+		want     []string
+	}{
+		{
+			name:     "declared and required",
+			declared: []string{"mimgui"},
+			required: []string{"mimgui"},
+			want:     []string{},
+		},
+		{
+			name:     "declared with different case",
+			declared: []string{"MIMGUI"},
+			required: []string{"mimgui"},
+			want:     []string{},
+		},
+		{
+			name:     "declared and optionally required",
+			declared: []string{"mimgui"},
+			optional: []string{"mimgui"},
+			want:     []string{},
+		},
+		{
+			name:     "declared but never required",
+			declared: []string{"mimgui", "vkeys"},
+			required: []string{"mimgui"},
+			want:     []string{"declared-dependency-unused: declared dependency vkeys is never required"},
+		},
+		{
+			name:     "required but not declared",
+			declared: []string{"mimgui"},
+			required: []string{"mimgui", "vkeys"},
+			want:     []string{"declared-dependency-missing: required dependency vkeys is not declared"},
+		},
+		{
+			name:     "declared but not in the registry",
+			declared: []string{"mimgui", "imaginary"},
+			required: []string{"mimgui"},
+			want:     []string{"declared-dependency-unknown: declared dependency imaginary is not in the registry"},
+		},
+		{
+			name:     "runtime dependencies declared and required",
+			declared: []string{"SAMP", "SAMPFUNCS", "mimgui"},
+			required: []string{"samp", "sampfuncs", "mimgui"},
+			want:     []string{},
+		},
+		{
+			name:     "runtime dependencies declared but not required",
+			declared: []string{"SAMP", "CLEO", "mimgui"},
+			required: []string{"mimgui"},
+			want:     []string{},
+		},
+		{
+			name:     "runtime dependencies required but not declared",
+			declared: []string{"mimgui"},
+			required: []string{"samp", "moonloader", "mimgui"},
+			want:     []string{},
+		},
+		{
+			name:     "nothing declared",
+			required: []string{"mimgui"},
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &manifest.Manifest{
+				Script:               &manifest.Script{Dependencies: tt.declared},
+				Dependencies:         map[string]string{},
+				OptionalDependencies: map[string]string{},
+			}
+			for _, id := range tt.required {
+				m.Dependencies[id] = "1.0.0"
+			}
+			for _, id := range tt.optional {
+				m.OptionalDependencies[id] = "1.0.0"
+			}
+
+			got := []string{}
+			for _, issue := range CheckDeclaredDependencies(m, reg) {
+				if issue.File != "dep.json" {
+					t.Errorf("issue file = %q, want dep.json", issue.File)
+				}
+				got = append(got, issue.Rule+": "+issue.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
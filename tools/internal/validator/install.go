@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+)
+
+func CheckInstall(m *manifest.Manifest, itemType string) []*Issue {
+	issues := []*Issue{}
+	if m.Install != nil {
+		fieldIssues, ok := checkInstallFields(m)
+		issues = append(issues, fieldIssues...)
+		if !ok {
+			return issues
+		}
+	}
+
+	layout := m.InstallLayout(itemType)
+	files := []string{}
+	for file := range layout {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	claimed := make(map[string]string)
+	scriptInstalled := false
+	for _, file := range files {
+		destination := layout[file]
+
+		key := strings.ToLower(destination)
+		if other, ok := claimed[key]; ok {
+			issues = append(issues, &Issue{
+				File:    file,
+				Rule:    RuleInstallConflict,
+				Fatal:   true,
+				Message: fmt.Sprintf("installs to %s, which %s also installs to", destination, other),
+			})
+			continue
+		}
+		claimed[key] = file
+
+		ext := strings.ToLower(path.Ext(file))
+		if (ext == ".dll" || ext == ".so") && manifest.InstallRootOf(destination) != manifest.RootLib {
+			issues = append(issues, &Issue{
+				File:    file,
+				Rule:    RuleInstallFile,
+				Message: fmt.Sprintf("native module installed to %s cannot be required, MoonLoader only loads DLLs from %s", destination, manifest.RootLib),
+			})
+		}
+		if (ext == ".lua" || ext == ".luac") && path.Dir(destination) == manifest.RootMoonLoader {
+			scriptInstalled = true
+		}
+	}
+
+	if itemType == "scripts" && !scriptInstalled {
+		issues = append(issues, &Issue{
+			File:    "dep.json",
+			Rule:    RuleInstallFile,
+			Message: fmt.Sprintf("no Lua file is installed directly into %s, MoonLoader will not load this script", manifest.RootMoonLoader),
+		})
+	}
+
+	return issues
+}
+
+func checkInstallFields(m *manifest.Manifest) ([]*Issue, bool) {
+	issues := []*Issue{}
+
+	if path.Clean(m.Install.Root) != m.Install.Root || manifest.InstallRootOf(m.Install.Root) == "" {
+		issues = append(issues, &Issue{
+			File:    "dep.json",
+			Rule:    RuleInstallRoot,
+			Fatal:   true,
+			Message: fmt.Sprintf("install.root %q must be a clean path inside one of %s", m.Install.Root, strings.Join(manifest.InstallRoots, ", ")),
+		})
+		return issues, false
+	}
+
+	listed := []string{}
+	for file := range m.Install.Files {
+		listed = append(listed, file)
+	}
+	sort.Strings(listed)
+
+	for _, file := range listed {
+		if _, ok := m.Files[file]; !ok {
+			issues = append(issues, &Issue{
+				File:    "dep.json",
+				Rule:    RuleInstallFile,
+				Fatal:   true,
+				Message: fmt.Sprintf("install.files lists %s which is not in files", file),
+			})
+			continue
+		}
+
+		destination := m.Install.Files[file]
+		if path.Clean(destination) != destination || manifest.InstallRootOf(path.Dir(destination)) == "" {
+			issues = append(issues, &Issue{
+				File:    file,
+				Rule:    RuleInstallFile,
+				Fatal:   true,
+				Message: fmt.Sprintf("destination %q must be a clean path inside one of %s", destination, strings.Join(manifest.InstallRoots, ", ")),
+			})
+		}
+	}
+
+	return issues, true
+}
@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+)
+
+func TestCheckInstall(t *testing.T) {
+	files := func(names ...string) map[string]manifest.FileInfo {
+		m := make(map[string]manifest.FileInfo)
+		for _, name := range names {
+			m[name] = manifest.FileInfo{}
+		}
+		return m
+	}
+
+	tests := []struct {
+		name     string
+		itemType string
+		manifest manifest.Manifest
+		want     []string
+	}{
+		{
+			name:     "inferred script layout",
+			itemType: "scripts",
+			manifest: manifest.Manifest{ID: "hud", Files: files("hud.lua", "hud.ini")},
+			want:     []string{},
+		},
+		{
+			name:     "inferred layout with a case conflict",
+			itemType: "scripts",
+			manifest: manifest.Manifest{ID: "hud", Files: files("hud.lua", "config/hud.ini", "HUD.ini")},
+			want:     []string{"install-conflict config/hud.ini"},
+		},
+		{
+			name:     "inferred layout with a DLL outside lib",
+			itemType: "scripts",
+			manifest: manifest.Manifest{ID: "hud", Files: files("hud.lua", "bin/helper.dll")},
+			want:     []string{"install-file bin/helper.dll"},
+		},
+		{
+			name:     "inferred script layout without a top-level script",
+			itemType: "scripts",
+			manifest: manifest.Manifest{ID: "hud", Files: files("src/hud.lua")},
+			want:     []string{"install-file dep.json"},
+		},
+		{
+			name:     "explicit root outside moonloader",
+			itemType: "deps",
+			manifest: manifest.Manifest{ID: "lfs", Files: files("lfs.dll"), Install: &manifest.Install{Root: "C:/Windows"}},
+			want:     []string{"install-root dep.json"},
+		},
+		{
+			name:     "explicit destination for an unknown file",
+			itemType: "deps",
+			manifest: manifest.Manifest{ID: "lfs", Files: files("lfs.dll"), Install: &manifest.Install{Root: manifest.RootLib, Files: map[string]string{"missing.dll": "moonloader/lib/missing.dll"}}},
+			want:     []string{"install-file dep.json"},
+		},
+		{
+			name:     "explicit destinations that collide",
+			itemType: "deps",
+			manifest: manifest.Manifest{ID: "a", Files: files("a.lua", "b.lua"), Install: &manifest.Install{Root: manifest.RootLib, Files: map[string]string{"b.lua": "moonloader/lib/A.lua"}}},
+			want:     []string{"install-conflict b.lua"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, issue := range CheckInstall(&tt.manifest, tt.itemType) {
+				got = append(got, issue.Rule+" "+issue.File)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package validator
+
+import "fmt"
+
+const (
+	RuleManifest             = "manifest"
+	RuleAnalysis             = "analysis"
+	RuleNativeUnreadable     = "native-unreadable"
+	RuleNativeArchitecture   = "native-architecture"
+	RuleNativeLuaOpen        = "native-luaopen"
+	RuleNativeImport         = "native-import"
+	RuleNativeOutdated       = "native-outdated"
+	RuleCompatSyntax         = "compat-syntax"
+	RuleCompatLibrary        = "compat-library"
+	RuleCompatOutdated       = "compat-outdated"
+	RuleUnknownDeclared      = "declared-dependency-unknown"
+	RuleUnusedDeclared       = "declared-dependency-unused"
+	RuleUndeclaredDependency = "declared-dependency-missing"
+	RuleMissingModule        = "module-missing"
+	RuleUnreachableFile      = "module-unreachable"
+	RuleDynamicModules       = "module-dynamic"
+	RuleSchema               = "manifest-schema"
+	RuleVersionRange         = "version-range"
+	RuleVersionUnsatisfiable = "version-unsatisfiable"
+	RuleDependencyConflict   = "dependency-conflict"
+	RuleInstallRoot          = "install-root"
+	RuleInstallFile          = "install-file"
+	RuleInstallConflict      = "install-conflict"
+)
+
+type Issue struct {
+	File    string
+	Line    int
+	Column  int
+	Rule    string
+	Fatal   bool
+	Message string
+}
+
+func (i *Issue) Location() string {
+	if i.Line > 0 && i.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d", i.File, i.Line, i.Column)
+	}
+	if i.Line > 0 {
+		return fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	return i.File
+}
+
+func (i *Issue) Error() string {
+	return fmt.Sprintf("%s: %s", i.Location(), i.Message)
+}
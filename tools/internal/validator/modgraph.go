@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+	"github.com/Deps-Tech/deps-registry/tools/internal/native"
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+)
+
+func InspectModuleGraph(m *manifest.Manifest, analysis *parser.Analysis, natives map[string]manifest.NativeInfo) []*Issue {
+	graph := analysis.ModuleGraph
+	issues := []*Issue{}
+
+	for _, req := range graph.Missing {
+		if nativeProvides(natives, req.Module) {
+			continue
+		}
+		issues = append(issues, &Issue{
+			File:    req.File,
+			Line:    req.Line,
+			Column:  req.Column,
+			Rule:    RuleMissingModule,
+			Fatal:   !req.Optional,
+			Message: fmt.Sprintf("requires internal module %s which is not shipped with the package", req.Module),
+		})
+	}
+
+	if graph.Dynamic {
+		issues = append(issues, &Issue{
+			File:    "dep.json",
+			Rule:    RuleDynamicModules,
+			Message: "modules are required dynamically, unreachable files were not checked",
+		})
+		return issues
+	}
+
+	entries := graph.EntryPoints(m.ID, m.Provides)
+	for _, file := range graph.Unreachable(entries) {
+		issues = append(issues, &Issue{
+			File:    file,
+			Rule:    RuleUnreachableFile,
+			Message: fmt.Sprintf("not reachable from %s and not listed in provides", strings.Join(entries, ", ")),
+		})
+	}
+
+	return issues
+}
+
+func nativeProvides(natives map[string]manifest.NativeInfo, module string) bool {
+	symbol := native.LuaOpenSymbol(module)
+	for _, info := range natives {
+		for _, export := range info.LuaOpen {
+			if export == symbol {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+	"github.com/Deps-Tech/deps-registry/tools/internal/native"
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+)
+
+var runtimeLibraryPrefixes = []string{
+	"api-ms-win-",
+	"lua51",
+	"msvcp",
+	"msvcr",
+	"ucrtbase",
+	"vcruntime",
+}
+
+func InspectNative(path string, m *manifest.Manifest) (map[string]manifest.NativeInfo, []*Issue) {
+	section := make(map[string]manifest.NativeInfo)
+	issues := []*Issue{}
+
+	files := []string{}
+	shipped := make(map[string]bool)
+	for file := range m.Files {
+		if strings.EqualFold(filepath.Ext(file), ".dll") {
+			files = append(files, file)
+			shipped[parser.LibraryName(file)] = true
+		}
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		info, err := native.Inspect(filepath.Join(path, file))
+		if err != nil {
+			issues = append(issues, &Issue{File: file, Rule: RuleNativeUnreadable, Fatal: true, Message: err.Error()})
+			continue
+		}
+
+		section[file] = manifest.NativeInfo{
+			Machine: info.Machine,
+			LuaOpen: info.LuaOpen,
+			Imports: info.Imports,
+		}
+
+		if info.Machine != native.MachineX86 {
+			issues = append(issues, &Issue{
+				File:    file,
+				Rule:    RuleNativeArchitecture,
+				Fatal:   true,
+				Message: fmt.Sprintf("built for %s, MoonLoader requires 32-bit x86", info.Machine),
+			})
+		}
+
+		if len(info.LuaOpen) > 0 && !exportsClaimedModule(info, file, m) {
+			issues = append(issues, &Issue{
+				File:    file,
+				Rule:    RuleNativeLuaOpen,
+				Message: fmt.Sprintf("exports %s, none match the modules it provides", strings.Join(info.LuaOpen, ", ")),
+			})
+		}
+
+		for _, lib := range info.Imports {
+			if parser.IsSystemLibrary(lib) || isRuntimeLibrary(lib) || shipped[parser.LibraryName(lib)] {
+				continue
+			}
+			issues = append(issues, &Issue{
+				File:    file,
+				Rule:    RuleNativeImport,
+				Message: fmt.Sprintf("imports %s which is neither a system library nor shipped with the package", lib),
+			})
+		}
+	}
+
+	for _, file := range files {
+		recorded, ok := m.Native[file]
+		if !ok {
+			continue
+		}
+		if current, ok := section[file]; ok && !nativeInfoEqual(recorded, current) {
+			issues = append(issues, &Issue{
+				File:    file,
+				Rule:    RuleNativeOutdated,
+				Message: "native section is out of date",
+			})
+		}
+	}
+
+	return section, issues
+}
+
+func exportsClaimedModule(info *native.Info, file string, m *manifest.Manifest) bool {
+	module := parser.CanonicalModule(file)
+	claims := []string{m.ID, module, m.ID + "." + module}
+	for _, alias := range m.Provides {
+		claims = append(claims, alias, alias+"."+module)
+	}
+
+	for _, claim := range claims {
+		symbol := native.LuaOpenSymbol(claim)
+		for _, export := range info.LuaOpen {
+			if strings.EqualFold(export, symbol) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isRuntimeLibrary(name string) bool {
+	name = strings.ToLower(name)
+	for _, prefix := range runtimeLibraryPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func nativeInfoEqual(a, b manifest.NativeInfo) bool {
+	return a.Machine == b.Machine &&
+		strings.Join(a.LuaOpen, ",") == strings.Join(b.LuaOpen, ",") &&
+		strings.Join(a.Imports, ",") == strings.Join(b.Imports, ",")
+}
+
+func NativeSectionsEqual(a, b map[string]manifest.NativeInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for file, info := range a {
+		other, ok := b[file]
+		if !ok || !nativeInfoEqual(info, other) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"sort"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+)
+
+func InspectSources(analysis *parser.Analysis) []*Issue {
+	issues := []*Issue{}
+
+	analyzers := []string{}
+	for name := range analysis.Findings {
+		analyzers = append(analyzers, name)
+	}
+	sort.Strings(analyzers)
+
+	for _, name := range analyzers {
+		for _, w := range analysis.Findings[name] {
+			if !w.Type.IsOpacity() {
+				continue
+			}
+			issues = append(issues, &Issue{
+				File:    w.File,
+				Line:    w.Line,
+				Column:  w.Column,
+				Rule:    w.RuleID(),
+				Fatal:   w.Severity == parser.SeverityError,
+				Message: w.Message,
+			})
+		}
+	}
+
+	return issues
+}
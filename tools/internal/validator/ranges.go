@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+	"github.com/Deps-Tech/deps-registry/tools/internal/versioning"
+)
+
+func CheckVersionRanges(m *manifest.Manifest, available map[string][]string) []*Issue {
+	issues := []*Issue{}
+	ranges := make(map[string]map[string]*versioning.Range)
+
+	sections := []string{}
+	for section := range m.DependencyRanges() {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+
+	for _, section := range sections {
+		entries := m.DependencyRanges()[section]
+		ids := make([]string, 0, len(entries))
+		for id := range entries {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		ranges[section] = make(map[string]*versioning.Range)
+		for _, id := range ids {
+			r, err := versioning.ParseRange(entries[id])
+			if err != nil {
+				issues = append(issues, &Issue{
+					File:    "dep.json",
+					Rule:    RuleVersionRange,
+					Fatal:   true,
+					Message: fmt.Sprintf("%s.%s: %v", section, id, err),
+				})
+				continue
+			}
+			ranges[section][id] = r
+
+			if id == m.ID {
+				issues = append(issues, &Issue{
+					File:    "dep.json",
+					Rule:    RuleDependencyConflict,
+					Fatal:   true,
+					Message: fmt.Sprintf("%s lists the package itself", section),
+				})
+				continue
+			}
+
+			versions, known := available[id]
+			if !known || section == "conflicts" || section == "replaces" {
+				continue
+			}
+			if len(r.Matching(versions)) == 0 {
+				issues = append(issues, &Issue{
+					File:    "dep.json",
+					Rule:    RuleVersionUnsatisfiable,
+					Fatal:   section == "dependencies",
+					Message: fmt.Sprintf("%s.%s: no version of %s matches %s (available: %s)", section, id, id, r, strings.Join(versioning.Sort(versions), ", ")),
+				})
+			}
+		}
+	}
+
+	for _, section := range []string{"dependencies", "optionalDependencies", "peerDependencies"} {
+		ids := make([]string, 0, len(ranges[section]))
+		for id := range ranges[section] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			if _, ok := ranges["replaces"][id]; ok {
+				issues = append(issues, &Issue{
+					File:    "dep.json",
+					Rule:    RuleDependencyConflict,
+					Fatal:   true,
+					Message: fmt.Sprintf("%s.%s: package both depends on and replaces %s", section, id, id),
+				})
+			}
+
+			conflict, ok := ranges["conflicts"][id]
+			if !ok {
+				continue
+			}
+			required := ranges[section][id]
+			overlap := conflict.Matching(required.Matching(available[id]))
+			if required.IsAny() || conflict.IsAny() || len(overlap) > 0 {
+				issues = append(issues, &Issue{
+					File:    "dep.json",
+					Rule:    RuleDependencyConflict,
+					Fatal:   true,
+					Message: fmt.Sprintf("%s.%s: %s overlaps conflicts.%s %s", section, id, required, id, conflict),
+				})
+			}
+		}
+	}
+
+	return issues
+}
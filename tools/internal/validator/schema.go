@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/manifest"
+)
+
+func InspectSchema(path string) []*Issue {
+	data, err := os.ReadFile(filepath.Join(path, "dep.json"))
+	if err != nil {
+		return []*Issue{{File: "dep.json", Rule: RuleSchema, Fatal: true, Message: err.Error()}}
+	}
+
+	return SchemaIssues(manifest.ValidateSchema(data))
+}
+
+func SchemaIssues(errors []*manifest.SchemaError) []*Issue {
+	issues := []*Issue{}
+	for _, e := range errors {
+		pointer := e.Pointer
+		if pointer == "" {
+			pointer = "/"
+		}
+		issues = append(issues, &Issue{
+			File:    "dep.json",
+			Line:    e.Line,
+			Column:  e.Column,
+			Rule:    RuleSchema,
+			Fatal:   true,
+			Message: pointer + ": " + e.Message,
+		})
+	}
+	return issues
+}
@@ -0,0 +1,29 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Deps-Tech/deps-registry/tools/internal/parser"
+)
+
+func CheckUnresolved(analysis *parser.Analysis, strict bool) []*Issue {
+	issues := []*Issue{}
+
+	for _, dep := range analysis.Unresolved {
+		message := fmt.Sprintf("require %q does not resolve to any package in the registry", dep.Module)
+		if len(dep.Suggestions) > 0 {
+			message += fmt.Sprintf(" (did you mean %s?)", strings.Join(dep.Suggestions, ", "))
+		}
+		issues = append(issues, &Issue{
+			File:    dep.File,
+			Line:    dep.Line,
+			Column:  dep.Column,
+			Rule:    parser.WarningUnresolvedModule.RuleID(),
+			Fatal:   strict && !dep.Optional,
+			Message: message,
+		})
+	}
+
+	return issues
+}
@@ -0,0 +1,88 @@
+package versioning
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const AnyVersion = "*"
+
+type Range struct {
+	raw         string
+	constraints *semver.Constraints
+}
+
+func ParseRange(value string) (*Range, error) {
+	raw := strings.TrimSpace(value)
+	if raw == "" {
+		raw = AnyVersion
+	}
+
+	constraints, err := semver.NewConstraint(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version range %q", value)
+	}
+
+	return &Range{raw: raw, constraints: constraints}, nil
+}
+
+func (r *Range) String() string {
+	return r.raw
+}
+
+func (r *Range) IsAny() bool {
+	return r.raw == AnyVersion
+}
+
+func (r *Range) Contains(version string) bool {
+	if r.IsAny() {
+		return true
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return r.raw == version
+	}
+	return r.constraints.Check(v)
+}
+
+func (r *Range) Matching(versions []string) []string {
+	matching := []string{}
+	for _, version := range versions {
+		if r.Contains(version) {
+			matching = append(matching, version)
+		}
+	}
+	return Sort(matching)
+}
+
+func Satisfies(version, constraint string) bool {
+	r, err := ParseRange(constraint)
+	if err != nil {
+		return false
+	}
+	return r.Contains(version)
+}
+
+func MaxSatisfying(versions []string, constraint string) string {
+	r, err := ParseRange(constraint)
+	if err != nil {
+		return ""
+	}
+	return GetLatest(r.Matching(versions))
+}
+
+func IsExact(constraint string) bool {
+	return constraint == AnyVersion || IsValid(constraint) && !strings.ContainsAny(constraint, "^~<>=*xX|, ")
+}
+
+func CaretRange(version string) string {
+	if version == "" || version == AnyVersion {
+		return AnyVersion
+	}
+	if !IsValid(version) {
+		return version
+	}
+	return "^" + version
+}
@@ -0,0 +1,99 @@
+package versioning
+
+import "testing"
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.2.3", "^1.0.0", true},
+		{"2.0.0", "^1.0.0", false},
+		{"1.2.9", "~1.2.0", true},
+		{"1.3.0", "~1.2.0", false},
+		{"1.0.0", ">=1.0.0, <2.0.0", true},
+		{"0.9.0", ">=1.0.0, <2.0.0", false},
+		{"1.0.0", "1.0.0", true},
+		{"1.0.1", "1.0.0", false},
+		{"2025.728.952", "*", true},
+		{"anything", "", true},
+		{"1.0.0", "not a range", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			if got := Satisfies(tt.version, tt.constraint); got != tt.want {
+				t.Errorf("Satisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxSatisfying(t *testing.T) {
+	versions := []string{"1.0", "1.2", "1.10.0", "2.0.0"}
+
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"^1.0.0", "1.10.0"},
+		{"~1.2", "1.2"},
+		{"*", "2.0.0"},
+		{"^3.0.0", ""},
+		{"not a range", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			if got := MaxSatisfying(versions, tt.constraint); got != tt.want {
+				t.Errorf("MaxSatisfying(%q) = %q, want %q", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsExact(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       bool
+	}{
+		{"1.0.0", true},
+		{"2025.728.952", true},
+		{"*", true},
+		{"^1.0.0", false},
+		{"~1.0.0", false},
+		{">=1.0.0", false},
+		{"1.x", false},
+		{"1.0.0 || 2.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			if got := IsExact(tt.constraint); got != tt.want {
+				t.Errorf("IsExact(%q) = %v, want %v", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaretRange(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.0.0", "^1.0.0"},
+		{"2025.728.952", "^2025.728.952"},
+		{"", "*"},
+		{"*", "*"},
+		{"latest", "latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := CaretRange(tt.version); got != tt.want {
+				t.Errorf("CaretRange(%q) = %q, want %q", tt.version, got, tt.want)
+			}
+		})
+	}
+}
@@ -52,7 +52,7 @@ func GetLatest(versions []string) string {
 	}
 
 	sort.Sort(semver.Collection(validVersions))
-	return validVersions[len(validVersions)-1].String()
+	return validVersions[len(validVersions)-1].Original()
 }
 
 func Sort(versions []string) []string {
@@ -0,0 +1,38 @@
+package versioning
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetLatest(t *testing.T) {
+	tests := []struct {
+		versions []string
+		want     string
+	}{
+		{[]string{"1.0.0", "1.10.0", "1.2.0"}, "1.10.0"},
+		{[]string{"1.0", "1.2"}, "1.2"},
+		{[]string{"1.0.0", "v2.0"}, "v2.0"},
+		{[]string{"2025.728.952", "2025.1001.1"}, "2025.1001.1"},
+		{[]string{"beta", "1.0"}, "1.0"},
+		{[]string{"alpha", "beta"}, "beta"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.versions, ","), func(t *testing.T) {
+			if got := GetLatest(tt.versions); got != tt.want {
+				t.Errorf("GetLatest(%v) = %q, want %q", tt.versions, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	got := Sort([]string{"1.10", "beta", "1.2.0", "1.0"})
+	want := []string{"1.0", "1.2.0", "1.10", "beta"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort = %v, want %v", got, want)
+	}
+}